@@ -1,7 +1,10 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"sort"
 	"strings"
 
@@ -17,9 +20,22 @@ func parseGoBinaryCMD() *cobra.Command {
 		binaryFile string // binary file path
 		topN       int    // show top N information
 		grep       string // grep symbol name
-		sortName   string // info sort, size, address, or symbol
-		isAsc      bool   // sort order, true: asc, false: desc
+		sortName   string // sort keys of symbols, e.g. size, address, symbol, module
+		isAsc      bool   // sort order of symbols, true: asc, false: desc
+		pkgSort    string // sort keys of packages, e.g. size, name, lines
+		isPkgAsc   bool   // sort order of packages, true: asc, false: desc
 		maxWidth   int    // max width of output
+		format     string // output format, text, json, html, pprof or folded
+		outputFile string // output file of html, pprof or folded report
+		isSection  bool   // show section breakdown
+		budgetFile string // size budget file
+		isGeneric  bool   // show generic instantiations
+		isKind     bool   // show size summary by symbol kind
+		filter     = &parser.Filter{}
+		includes   []string // include patterns of symbol names and package paths
+		excludes   []string // exclude patterns of symbol names and package paths
+		buildOpts  = &parser.BuildOptions{}
+		label      string // record a snapshot with the label
 	)
 
 	cmd := &cobra.Command{
@@ -29,67 +45,275 @@ func parseGoBinaryCMD() *cobra.Command {
 		Example: color.HiBlackString(`  # Parse the binary file compiled by go
   goparser binary --binary-file=./your_binary_file
 
+  # Build the main package for linux/arm64 and parse the binary file
+  goparser binary --build=./cmd/server --goos=linux --goarch=arm64 --trimpath
+
   # Parse the binary file compiled by go and show top 30 information
   goparser binary --binary-file=./your_binary_file --top-n=30
 
+  # Parse the binary file compiled by go, sort the symbols by module then size, and sort the packages by name
+  goparser binary --binary-file=./your_binary_file --sort=module:asc,size:desc --pkg-sort=name --pkg-asc
+
   # Parse the binary file compiled by go and grep symbol name "sponge"
-  goparser binary --binary-file=./your_binary_file --grep=sponge`),
+  goparser binary --binary-file=./your_binary_file --grep=sponge
+
+  # Parse the binary file compiled by go, only show the symbols and packages of github.com, exclude the test packages
+  goparser binary --binary-file=./your_binary_file --include="glob:github.com/*" --exclude="_test$"
+
+  # Parse the binary file compiled by go and only show the text symbols larger than 1KB
+  goparser binary --binary-file=./your_binary_file --type=T,t --min-size=1024
+
+  # Parse the binary file compiled by go and show the size of each section
+  goparser binary --binary-file=./your_binary_file --section
+
+  # Parse the binary file compiled by go and show the generic functions that are instantiated with many shapes
+  goparser binary --binary-file=./your_binary_file --generic
+
+  # Parse the binary file compiled by go and show the size of functions, methods, closures, type metadata, etc.
+  goparser binary --binary-file=./your_binary_file --kind
+
+  # Parse the binary file compiled by go and output the result in json format
+  goparser binary --binary-file=./your_binary_file --format=json
+
+  # Parse the binary file compiled by go and generate a html treemap report
+  goparser binary --binary-file=./your_binary_file --format=html --output=report.html
+
+  # Parse the binary file compiled by go and record a snapshot of the package sizes, view the trend by "goparser binary history"
+  goparser binary --binary-file=./your_binary_file --record=v1.2.0
+
+  # Parse the binary file compiled by go and generate a pprof profile, view it by "go tool pprof -http=:8080 profile.pb.gz"
+  goparser binary --binary-file=./your_binary_file --format=pprof --output=profile.pb.gz
+
+  # Parse the binary file compiled by go and render a flame graph by flamegraph.pl
+  goparser binary --binary-file=./your_binary_file --format=folded | flamegraph.pl > flame.svg
+
+  # Parse the binary file compiled by go and exit with non-zero code if the size budget is exceeded
+  goparser binary --binary-file=./your_binary_file --budget=./budget.json`),
 		SilenceErrors: true,
 		SilenceUsage:  true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			defer func() {
-				if e := recover(); e != nil {
-					fmt.Println("Error:", checkErr(e.(error)))
-				}
-			}()
-
 			if maxWidth < 50 {
 				maxWidth = 50
 			} else if maxWidth > 256 {
 				maxWidth = 256
 			}
 
-			bp, err := parser.NewBinaryParser(binaryFile, grep)
+			if binaryFile == "" && buildOpts.Pkg == "" {
+				return errors.New("either --binary-file or --build must be specified")
+			}
+			if binaryFile != "" && buildOpts.Pkg != "" {
+				return errors.New("--binary-file and --build cannot be used together")
+			}
+
+			if label != "" {
+				// the snapshot records the whole binary file, so the history can be compared
+				if flags := changedFlags(cmd, filterFlagNames); len(flags) > 0 {
+					return fmt.Errorf("--record cannot be used with the filter flags %s", strings.Join(flags, ", "))
+				}
+			}
+
+			var err error
+			var budget *parser.Budget
+			if budgetFile != "" {
+				// the budget is checked against the whole binary file
+				if flags := changedFlags(cmd, filterFlagNames); len(flags) > 0 {
+					return fmt.Errorf("--budget cannot be used with the filter flags %s", strings.Join(flags, ", "))
+				}
+				budget, err = parser.LoadBudget(budgetFile)
+				if err != nil {
+					return err
+				}
+			}
+
+			nmSortKeys, err := parser.ParseNmSortKeys(sortName, isAsc)
+			if err != nil {
+				return err
+			}
+			pkgSortKeys, err := parser.ParsePkgSortKeys(pkgSort, isPkgAsc)
+			if err != nil {
+				return err
+			}
+
+			filter.Include, err = parser.ParsePatterns(includes)
+			if err != nil {
+				return err
+			}
+			filter.Exclude, err = parser.ParsePatterns(excludes)
+			if err != nil {
+				return err
+			}
+
+			parseFile := binaryFile
+			if buildOpts.Pkg != "" {
+				tmpDir, err := os.MkdirTemp("", "goparser-build-")
+				if err != nil {
+					return err
+				}
+				defer os.RemoveAll(tmpDir) //nolint
+				parseFile, err = parser.BuildBinary(cmd.Context(), buildOpts, tmpDir)
+				if err != nil {
+					return err
+				}
+				binaryFile = buildOpts.Pkg + " (" + buildOpts.Platform() + ")"
+			}
+
+			bp, err := parser.NewBinaryParser(cmd.Context(), parseFile, parser.WithGrep(grep), parser.WithFilter(filter))
 			if err != nil {
-				panic(err)
+				return checkErr(err)
 			}
-			bp.MaxWidth = maxWidth
 
-			sortName = strings.ToLower(sortName)
-			switch sortName {
-			case "address", "addr":
-				sort.Sort(parser.ByNmAddress{NmParsers: bp.NmParsers, IsAsc: isAsc})
-			case "symbol", "sym":
-				sort.Sort(parser.ByNmSymbol{NmParsers: bp.NmParsers, IsAsc: isAsc})
+			sort.Stable(parser.ByNmKeys{NmParsers: bp.NmParsers, Keys: nmSortKeys})
+			sort.Stable(parser.ByPkgKeys{PkgInfos: bp.PkgInfos, Keys: pkgSortKeys})
+
+			var renderer parser.Renderer
+			report := bp.Report(binaryFile, 0)
+			switch strings.ToLower(format) {
+			case "json":
+				renderer = &parser.JSONRenderer{}
+				report = bp.Report(binaryFile, topN)
+			case "html":
+				renderer = &parser.HTMLRenderer{}
+				if outputFile == "" {
+					outputFile = "report.html"
+				}
+			case "pprof":
+				renderer = &parser.PprofRenderer{}
+				if outputFile == "" {
+					outputFile = "profile.pb.gz"
+				}
+			case "folded":
+				renderer = &parser.FoldedRenderer{}
 			default:
-				sort.Sort(parser.ByNmSize{NmParsers: bp.NmParsers, IsAsc: isAsc})
+				renderer = &parser.TextRenderer{
+					TopN:      topN,
+					MaxWidth:  maxWidth,
+					NoColor:   outputFile != "",
+					IsSection: isSection,
+					IsKind:    isKind,
+					IsGeneric: isGeneric,
+				}
+			}
+
+			if outputFile == "" {
+				err = renderer.Render(os.Stdout, report)
+			} else {
+				err = writeFile(outputFile, func(w io.Writer) error { return renderer.Render(w, report) })
+			}
+			if err != nil {
+				return err
+			}
+			if outputFile != "" {
+				fmt.Printf("%s report has been written to %s\n", strings.ToLower(format), outputFile)
+				if strings.EqualFold(format, "pprof") {
+					fmt.Printf("view it by \"go tool pprof -http=:8080 %s\"\n", outputFile)
+				}
+			}
+
+			if label != "" {
+				err = parser.SaveSnapshot(cacheGoLibDir, bp.Snapshot(binaryFile, label))
+				if err != nil {
+					return err
+				}
+				if f := strings.ToLower(format); f != "json" && f != "folded" {
+					fmt.Printf("\nsnapshot %s has been recorded\n", color.HiCyanString(label))
+				}
 			}
-			sort.Sort(parser.ByPkgSize{PkgInfos: bp.PkgInfos})
 
-			bp.PrintNmParser(binaryFile, topN)
-			fmt.Printf("\n\n")
-			bp.PrintPkgInfo(binaryFile, topN)
+			if budget != nil {
+				violations := bp.CheckBudget(budget)
+				if len(violations) > 0 {
+					// print to stderr so as not to break the json output
+					cmd.PrintErrln()
+					for _, v := range violations {
+						cmd.PrintErrln(color.HiRedString("  budget exceeded: ") + v.String())
+					}
+					cmd.PrintErrln()
+					return fmt.Errorf("%d size budget violations found", len(violations))
+				}
+			}
 
 			return nil
 		},
 	}
 
+	cmd.AddCommand(diffGoBinaryCMD(), whyGoBinaryCMD(), matrixGoBinaryCMD(), historyGoBinaryCMD(), compareRevGoBinaryCMD())
+
 	cmd.Flags().StringVarP(&binaryFile, "binary-file", "f", "", "binary file path")
-	_ = cmd.MarkFlagRequired("binary-file")
+	cmd.Flags().StringVar(&buildOpts.Pkg, "build", "", "build the main package into a temporary directory and parse it, e.g. ./cmd/server")
+	cmd.Flags().StringVar(&buildOpts.GOOS, "goos", "", "target operating system of the build, default is the host")
+	cmd.Flags().StringVar(&buildOpts.GOARCH, "goarch", "", "target architecture of the build, default is the host")
+	cmd.Flags().StringSliceVar(&buildOpts.Tags, "tags", nil, "build tags of the build")
+	cmd.Flags().BoolVar(&buildOpts.TrimPath, "trimpath", false, "remove file system paths from the binary file of the build")
+	cmd.Flags().StringVar(&buildOpts.LDFlags, "ldflags", "", "linker flags of the build, -s and -w are ignored to retain symbols")
 	cmd.Flags().IntVarP(&topN, "top-n", "n", 100, "show top N information")
 	cmd.Flags().StringVarP(&grep, "grep", "g", "", "grep symbol name")
-	cmd.Flags().StringVarP(&sortName, "sort", "s", "size", "info sort, size, address, or symbol")
-	cmd.Flags().BoolVarP(&isAsc, "asc", "a", false, "sort order, true: asc, false: desc")
+	cmd.Flags().StringArrayVar(&includes, "include", nil, "only keep the symbol names and package paths that match the regular expression, use prefix \"glob:\" for glob pattern, repeatable")
+	cmd.Flags().StringArrayVar(&excludes, "exclude", nil, "exclude the symbol names and package paths that match the regular expression, use prefix \"glob:\" for glob pattern, repeatable")
+	cmd.Flags().StringSliceVar(&filter.Types, "type", nil, "only keep the symbols of these nm types, e.g. T,t,D,B,R,U")
+	cmd.Flags().StringSliceVar(&filter.ExcludeTypes, "exclude-type", nil, "exclude the symbols of these nm types")
+	cmd.Flags().IntVar(&filter.MinSize, "min-size", 0, "only keep the symbols whose size is not less than this value, unit: bytes")
+	cmd.Flags().Float32Var(&filter.MinPercentage, "min-percentage", 0, "only keep the symbols whose percentage of total size is not less than this value, e.g. 0.01")
+	cmd.Flags().StringVarP(&sortName, "sort", "s", "size", "sort keys of symbols separated by comma, size, address, symbol, type, kind, package or module, a key can be suffixed with :asc or :desc, e.g. module,size:desc")
+	cmd.Flags().BoolVarP(&isAsc, "asc", "a", false, "sort order of symbols, true: asc, false: desc")
+	cmd.Flags().StringVar(&pkgSort, "pkg-sort", "size", "sort keys of packages separated by comma, size, name, lines or version, a key can be suffixed with :asc or :desc")
+	cmd.Flags().BoolVar(&isPkgAsc, "pkg-asc", false, "sort order of packages, true: asc, false: desc")
 	cmd.Flags().IntVarP(&maxWidth, "max-width", "w", 60, "max width of output")
+	cmd.Flags().BoolVarP(&isSection, "section", "e", false, "show the size of each section and the bytes not attributed to any symbol")
+	cmd.Flags().BoolVar(&isGeneric, "generic", false, "group the instantiations of generic functions and methods by their generic definition")
+	cmd.Flags().BoolVar(&isKind, "kind", false, "show the size summary by symbol kind, e.g. function, method, closure, type metadata")
+	cmd.Flags().StringVarP(&format, "format", "t", "text", "output format, text, json, html, pprof or folded")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "output file of the report, default is report.html for html, profile.pb.gz for pprof and stdout for others")
+	cmd.Flags().StringVarP(&label, "record", "r", "", "record a snapshot of the package sizes with the label, e.g. a version or commit")
+	cmd.Flags().StringVarP(&budgetFile, "budget", "b", "", "size budget file in json format, exit with non-zero code if the budget is exceeded")
 
 	return cmd
 }
 
+func writeFile(file string, write func(w io.Writer) error) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// the flags that drop symbols from the result
+var filterFlagNames = []string{"grep", "include", "exclude", "type", "exclude-type", "min-size", "min-percentage"}
+
+// get the flags that are set in the command line, e.g. --grep
+func changedFlags(cmd *cobra.Command, names []string) []string {
+	var flags []string
+	for _, name := range names {
+		if cmd.Flags().Changed(name) {
+			flags = append(flags, "--"+name)
+		}
+	}
+	return flags
+}
+
+// parse the include and exclude patterns of symbol names and package paths into a filter
+func newPatternFilter(includes []string, excludes []string) (*parser.Filter, error) {
+	include, err := parser.ParsePatterns(includes)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := parser.ParsePatterns(excludes)
+	if err != nil {
+		return nil, err
+	}
+	return &parser.Filter{Include: include, Exclude: exclude}, nil
+}
+
 func checkErr(err error) error {
-	if strings.Contains(err.Error(), "no symbols") {
-		paramTip := color.HiRedString(`-ldflags "-s -w"`)
-		cmdTip := color.HiCyanString("go build")
-		return fmt.Errorf("there is no symbol information in the compiled binary file, please do not use the parameter %s for the %s command.", paramTip, cmdTip)
+	if errors.Is(err, parser.ErrNoSymbols) {
+		tableTip := color.HiRedString("symbol table")
+		pclntabTip := color.HiRedString(".gopclntab")
+		return fmt.Errorf("neither a %s nor a %s section is found, the file is probably not a binary file compiled by go.", tableTip, pclntabTip)
 	}
 	return err
 }
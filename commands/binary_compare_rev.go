@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/zhufuyi/goparser/parser"
+)
+
+// compare the go binary files built from two git revisions command
+func compareRevGoBinaryCMD() *cobra.Command {
+	var (
+		topN      int      // show top N information
+		grep      string   // grep symbol name
+		includes  []string // include patterns of symbol names and package paths
+		excludes  []string // exclude patterns of symbol names and package paths
+		maxWidth  int      // max width of output
+		format    string   // output format, text or json
+		buildOpts = &parser.BuildOptions{}
+	)
+
+	cmd := &cobra.Command{
+		Use:   "compare-rev <base-revision> <head-revision>",
+		Short: "Compare the binary files built from two git revisions",
+		Long:  "Compare the binary files built from two git revisions, both revisions are checked out in temporary worktrees and built with identical settings, report the package and symbol deltas.",
+		Example: color.HiBlackString(`  # Compare the binary files built from the main branch and the current commit
+  goparser binary compare-rev main HEAD --pkg=./cmd/app
+
+  # Compare the binary files built for linux/arm64 from two tags
+  goparser binary compare-rev v1.0.0 v1.1.0 --pkg=./cmd/app --goos=linux --goarch=arm64 --trimpath`),
+		Args:          cobra.ExactArgs(2),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if maxWidth < 50 {
+				maxWidth = 50
+			} else if maxWidth > 256 {
+				maxWidth = 256
+			}
+
+			filter, err := newPatternFilter(includes, excludes)
+			if err != nil {
+				return err
+			}
+
+			tmpDir, err := os.MkdirTemp("", "goparser-rev-")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(tmpDir) //nolint
+
+			var bps []*parser.BinaryParser
+			for i, revision := range args {
+				var p *WaitPrinter // no waiting tip in json output
+				if !strings.EqualFold(format, "json") {
+					p = NewWaitPrinter(0)
+				}
+				p.LoopPrint(fmt.Sprintf("building %s at %s ", buildOpts.Pkg, revision))
+				file, err := parser.BuildRevision(cmd.Context(), ".", revision, buildOpts, filepath.Join(tmpDir, fmt.Sprintf("rev%d", i)))
+				p.StopPrint("")
+				if err != nil {
+					return err
+				}
+				bp, err := parser.NewBinaryParser(cmd.Context(), file, parser.WithGrep(grep), parser.WithFilter(filter))
+				if err != nil {
+					return checkErr(err)
+				}
+				bps = append(bps, bp)
+			}
+			bps[1].MaxWidth = maxWidth
+
+			diff := parser.DiffBinaryParser(args[0], bps[0], args[1], bps[1])
+			switch strings.ToLower(format) {
+			case "json":
+				return diff.PrintJSON(os.Stdout)
+			default:
+				diff.Print(os.Stdout, topN)
+			}
+			fmt.Println()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&buildOpts.Pkg, "pkg", "p", ".", "main package to build, relative to the current directory, e.g. ./cmd/app")
+	cmd.Flags().StringVar(&buildOpts.GOOS, "goos", "", "target operating system of the build, default is the host")
+	cmd.Flags().StringVar(&buildOpts.GOARCH, "goarch", "", "target architecture of the build, default is the host")
+	cmd.Flags().StringSliceVar(&buildOpts.Tags, "tags", nil, "build tags of the build")
+	cmd.Flags().BoolVar(&buildOpts.TrimPath, "trimpath", false, "remove file system paths from the binary files of the build")
+	cmd.Flags().StringVar(&buildOpts.LDFlags, "ldflags", "", "linker flags of the build, -s and -w are ignored to retain symbols")
+	cmd.Flags().IntVarP(&topN, "top-n", "n", 100, "show top N information")
+	cmd.Flags().StringVarP(&grep, "grep", "g", "", "grep symbol name")
+	cmd.Flags().StringArrayVar(&includes, "include", nil, "only keep the symbol names and package paths that match the regular expression, use prefix \"glob:\" for glob pattern, repeatable")
+	cmd.Flags().StringArrayVar(&excludes, "exclude", nil, "exclude the symbol names and package paths that match the regular expression, use prefix \"glob:\" for glob pattern, repeatable")
+	cmd.Flags().IntVarP(&maxWidth, "max-width", "w", 60, "max width of output")
+	cmd.Flags().StringVarP(&format, "format", "t", "text", "output format, text or json")
+
+	return cmd
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/zhufuyi/goparser/parser"
+)
+
+// diff two go binary files command
+func diffGoBinaryCMD() *cobra.Command {
+	var (
+		topN     int      // show top N information
+		grep     string   // grep symbol name
+		includes []string // include patterns of symbol names and package paths
+		excludes []string // exclude patterns of symbol names and package paths
+		maxWidth int      // max width of output
+		format   string   // output format, text or json
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff <old-binary-file> <new-binary-file>",
+		Short: "Diff two binary files compiled by go",
+		Long:  "Diff two binary files compiled by go, report added, removed, grown and shrunk symbols and packages.",
+		Example: color.HiBlackString(`  # Diff two binary files compiled by go
+  goparser binary diff ./old_binary_file ./new_binary_file
+
+  # Diff two binary files compiled by go and show top 30 information
+  goparser binary diff ./old_binary_file ./new_binary_file --top-n=30`),
+		Args:          cobra.ExactArgs(2),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if maxWidth < 50 {
+				maxWidth = 50
+			} else if maxWidth > 256 {
+				maxWidth = 256
+			}
+
+			filter, err := newPatternFilter(includes, excludes)
+			if err != nil {
+				return err
+			}
+
+			oldFile, newFile := args[0], args[1]
+			oldBP, err := parser.NewBinaryParser(cmd.Context(), oldFile, parser.WithGrep(grep), parser.WithFilter(filter))
+			if err != nil {
+				return checkErr(err)
+			}
+			newBP, err := parser.NewBinaryParser(cmd.Context(), newFile, parser.WithGrep(grep), parser.WithFilter(filter))
+			if err != nil {
+				return checkErr(err)
+			}
+			newBP.MaxWidth = maxWidth
+
+			diff := parser.DiffBinaryParser(oldFile, oldBP, newFile, newBP)
+			switch strings.ToLower(format) {
+			case "json":
+				return diff.PrintJSON(os.Stdout)
+			default:
+				diff.Print(os.Stdout, topN)
+			}
+			fmt.Println()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&topN, "top-n", "n", 100, "show top N information")
+	cmd.Flags().StringVarP(&grep, "grep", "g", "", "grep symbol name")
+	cmd.Flags().StringArrayVar(&includes, "include", nil, "only keep the symbol names and package paths that match the regular expression, use prefix \"glob:\" for glob pattern, repeatable")
+	cmd.Flags().StringArrayVar(&excludes, "exclude", nil, "exclude the symbol names and package paths that match the regular expression, use prefix \"glob:\" for glob pattern, repeatable")
+	cmd.Flags().IntVarP(&maxWidth, "max-width", "w", 60, "max width of output")
+	cmd.Flags().StringVarP(&format, "format", "t", "text", "output format, text or json")
+
+	return cmd
+}
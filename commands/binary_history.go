@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/zhufuyi/goparser/parser"
+)
+
+// show the size history of go binary files command
+func historyGoBinaryCMD() *cobra.Command {
+	var (
+		module   string // module path of the binary file
+		label    string // only show the snapshots with the label prefix
+		last     int    // show the last N snapshots
+		topN     int    // show top N information
+		maxWidth int    // max width of output
+		format   string // output format, text or json
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show the size trend of the recorded snapshots",
+		Long:  "Show the size trend of each package over the snapshots recorded by \"goparser binary --record\".",
+		Example: color.HiBlackString(`  # Show the size trend of the last 5 snapshots
+  goparser binary history
+
+  # Show the size trend of the last 10 snapshots of the module, the label starts with v1.
+  goparser binary history --module=github.com/foo/bar --label=v1. --last=10`),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if maxWidth < 50 {
+				maxWidth = 50
+			} else if maxWidth > 256 {
+				maxWidth = 256
+			}
+
+			snapshotsMap, err := parser.LoadSnapshots(cacheGoLibDir)
+			if err != nil {
+				return err
+			}
+			if len(snapshotsMap) == 0 {
+				return fmt.Errorf("no snapshot recorded, please use \"goparser binary --record\" to record a snapshot first")
+			}
+			if module == "" {
+				if len(snapshotsMap) > 1 {
+					var modules []string
+					for m := range snapshotsMap {
+						modules = append(modules, m)
+					}
+					sort.Strings(modules)
+					return fmt.Errorf("snapshots of multiple modules are recorded, please specify one by --module: %s", strings.Join(modules, ", "))
+				}
+				for m := range snapshotsMap {
+					module = m
+				}
+			}
+
+			var snapshots []*parser.Snapshot
+			for _, s := range snapshotsMap[module] {
+				if strings.HasPrefix(s.Label, label) {
+					snapshots = append(snapshots, s)
+				}
+			}
+			if len(snapshots) == 0 {
+				return fmt.Errorf("no snapshot of module %s found", module)
+			}
+			if last > 0 && len(snapshots) > last {
+				snapshots = snapshots[len(snapshots)-last:]
+			}
+
+			switch strings.ToLower(format) {
+			case "json":
+				data, err := json.MarshalIndent(snapshots, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+			default:
+				parser.PrintHistory(os.Stdout, snapshots, topN, maxWidth)
+				fmt.Println()
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&module, "module", "m", "", "module path of the binary file, can be omitted if only one module is recorded")
+	cmd.Flags().StringVarP(&label, "label", "l", "", "only show the snapshots whose label starts with this value")
+	cmd.Flags().IntVar(&last, "last", 5, "show the last N snapshots")
+	cmd.Flags().IntVarP(&topN, "top-n", "n", 100, "show top N information")
+	cmd.Flags().IntVarP(&maxWidth, "max-width", "w", 60, "max width of output")
+	cmd.Flags().StringVarP(&format, "format", "t", "text", "output format, text or json")
+
+	return cmd
+}
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/zhufuyi/goparser/parser"
+)
+
+// compare package sizes of go binary files for different platforms command
+func matrixGoBinaryCMD() *cobra.Command {
+	var (
+		platforms     []string // target platforms of the build
+		topN          int      // show top N information
+		grep          string   // grep symbol name
+		includes      []string // include patterns of symbol names and package paths
+		excludes      []string // exclude patterns of symbol names and package paths
+		maxWidth      int      // max width of output
+		format        string   // output format, text or json
+		isOnlyPartial bool     // only show the packages that appear on some platforms
+		buildOpts     = &parser.BuildOptions{}
+	)
+
+	cmd := &cobra.Command{
+		Use:   "matrix [[platform=]binary-file ...]",
+		Short: "Compare package sizes of binary files for different platforms",
+		Long:  "Compare package sizes of binary files for different platforms, the binary files are supplied or built, the packages that only appear on some platforms are highlighted.",
+		Example: color.HiBlackString(`  # Build the main package for linux/amd64, linux/arm64, darwin/arm64 and windows/amd64 and compare the package sizes
+  goparser binary matrix --build=./cmd/server
+
+  # Build the main package for the specified platforms and only show the packages that appear on some platforms
+  goparser binary matrix --build=./cmd/server --platforms=linux/amd64,windows/amd64 --only-partial
+
+  # Compare the package sizes of the supplied binary files
+  goparser binary matrix linux/amd64=./server_linux darwin/arm64=./server_darwin windows/amd64=./server.exe`),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if maxWidth < 50 {
+				maxWidth = 50
+			} else if maxWidth > 256 {
+				maxWidth = 256
+			}
+			if len(args) == 0 && buildOpts.Pkg == "" {
+				return errors.New("either binary files or --build must be specified")
+			}
+			if len(args) > 0 && buildOpts.Pkg != "" {
+				return errors.New("binary files and --build cannot be used together")
+			}
+
+			filter, err := newPatternFilter(includes, excludes)
+			if err != nil {
+				return err
+			}
+
+			var labels, files, parseFiles []string
+			if buildOpts.Pkg != "" {
+				tmpDir, err := os.MkdirTemp("", "goparser-matrix-")
+				if err != nil {
+					return err
+				}
+				defer os.RemoveAll(tmpDir) //nolint
+
+				for _, platform := range platforms {
+					goos, goarch, ok := strings.Cut(platform, "/")
+					if !ok || goos == "" || goarch == "" {
+						return fmt.Errorf("invalid platform %q, e.g. linux/amd64", platform)
+					}
+					opts := *buildOpts
+					opts.GOOS, opts.GOARCH = goos, goarch
+					var p *WaitPrinter // no waiting tip in json output
+					if !strings.EqualFold(format, "json") {
+						p = NewWaitPrinter(0)
+					}
+					p.LoopPrint(fmt.Sprintf("building %s for %s ", buildOpts.Pkg, platform))
+					file, err := parser.BuildBinary(cmd.Context(), &opts, tmpDir)
+					p.StopPrint("")
+					if err != nil {
+						return err
+					}
+					labels = append(labels, platform)
+					files = append(files, buildOpts.Pkg)
+					parseFiles = append(parseFiles, file)
+				}
+			} else {
+				for _, arg := range args {
+					label, file, ok := strings.Cut(arg, "=")
+					if !ok {
+						file, label = arg, filepath.Base(arg)
+					}
+					labels = append(labels, label)
+					files = append(files, file)
+					parseFiles = append(parseFiles, file)
+				}
+			}
+
+			var bps []*parser.BinaryParser
+			for _, file := range parseFiles {
+				bp, err := parser.NewBinaryParser(cmd.Context(), file, parser.WithGrep(grep), parser.WithFilter(filter))
+				if err != nil {
+					return checkErr(err)
+				}
+				bps = append(bps, bp)
+			}
+
+			m := parser.NewPlatformMatrix(labels, files, bps)
+			m.MaxWidth = maxWidth
+			switch strings.ToLower(format) {
+			case "json":
+				return m.PrintJSON(os.Stdout)
+			default:
+				m.Print(os.Stdout, topN, isOnlyPartial)
+			}
+			fmt.Println()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&buildOpts.Pkg, "build", "", "build the main package for each platform into a temporary directory, e.g. ./cmd/server")
+	cmd.Flags().StringSliceVarP(&platforms, "platforms", "p", []string{"linux/amd64", "linux/arm64", "darwin/arm64", "windows/amd64"}, "target platforms of the build")
+	cmd.Flags().StringSliceVar(&buildOpts.Tags, "tags", nil, "build tags of the build")
+	cmd.Flags().BoolVar(&buildOpts.TrimPath, "trimpath", false, "remove file system paths from the binary files of the build")
+	cmd.Flags().StringVar(&buildOpts.LDFlags, "ldflags", "", "linker flags of the build, -s and -w are ignored to retain symbols")
+	cmd.Flags().IntVarP(&topN, "top-n", "n", 100, "show top N information")
+	cmd.Flags().StringVarP(&grep, "grep", "g", "", "grep symbol name")
+	cmd.Flags().StringArrayVar(&includes, "include", nil, "only keep the symbol names and package paths that match the regular expression, use prefix \"glob:\" for glob pattern, repeatable")
+	cmd.Flags().StringArrayVar(&excludes, "exclude", nil, "exclude the symbol names and package paths that match the regular expression, use prefix \"glob:\" for glob pattern, repeatable")
+	cmd.Flags().IntVarP(&maxWidth, "max-width", "w", 60, "max width of output")
+	cmd.Flags().StringVarP(&format, "format", "t", "text", "output format, text or json")
+	cmd.Flags().BoolVar(&isOnlyPartial, "only-partial", false, "only show the packages that appear on some platforms")
+
+	return cmd
+}
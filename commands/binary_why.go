@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/zhufuyi/goparser/parser"
+)
+
+// explain why a package is linked into the go binary file command
+func whyGoBinaryCMD() *cobra.Command {
+	var (
+		binaryFile string   // binary file path
+		srcDir     string   // source directory of the module
+		mainPkg    string   // main package that the binary file is built from
+		maxPaths   int      // max number of import paths to show
+		format     string   // output format, text or json
+		includes   []string // include patterns of symbol names and package paths
+		excludes   []string // exclude patterns of symbol names and package paths
+	)
+
+	cmd := &cobra.Command{
+		Use:   "why <package>",
+		Short: "Explain why a package is linked into the binary file",
+		Long:  "Explain why a package is linked into the binary file, show the shortest import paths from the main package and the size they bring in.",
+		Example: color.HiBlackString(`  # Explain why package golang.org/x/net/html is linked into the binary file
+  goparser binary why golang.org/x/net/html --binary-file=./your_binary_file
+
+  # Explain why the packages of a module are linked into the binary file built from ./cmd/server
+  goparser binary why github.com/foo/bar --binary-file=./your_binary_file --dir=./your_module --pkg=./cmd/server`),
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := strings.TrimRight(args[0], "/")
+			if maxPaths < 1 {
+				maxPaths = 1
+			}
+
+			filter, err := newPatternFilter(includes, excludes)
+			if err != nil {
+				return err
+			}
+
+			bp, err := parser.NewBinaryParser(cmd.Context(), binaryFile, parser.WithFilter(filter))
+			if err != nil {
+				return checkErr(err)
+			}
+			pkgs, err := parser.GoListDeps(cmd.Context(), srcDir, mainPkg)
+			if err != nil {
+				return err
+			}
+			result, err := bp.Why(pkgs, target, maxPaths)
+			if err != nil {
+				return err
+			}
+
+			switch strings.ToLower(format) {
+			case "json":
+				return result.PrintJSON(os.Stdout)
+			default:
+				result.Print(os.Stdout, binaryFile, bp.TotalSize)
+			}
+			fmt.Println()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&binaryFile, "binary-file", "f", "", "binary file path")
+	_ = cmd.MarkFlagRequired("binary-file")
+	cmd.Flags().StringVarP(&srcDir, "dir", "d", ".", "source directory of the module that the binary file is built from")
+	cmd.Flags().StringVarP(&mainPkg, "pkg", "p", ".", "main package that the binary file is built from, relative to the source directory")
+	cmd.Flags().IntVarP(&maxPaths, "max-paths", "m", 5, "max number of import paths to show")
+	cmd.Flags().StringArrayVar(&includes, "include", nil, "only keep the symbol names and package paths that match the regular expression, use prefix \"glob:\" for glob pattern, repeatable")
+	cmd.Flags().StringArrayVar(&excludes, "exclude", nil, "exclude the symbol names and package paths that match the regular expression, use prefix \"glob:\" for glob pattern, repeatable")
+	cmd.Flags().StringVarP(&format, "format", "t", "text", "output format, text or json")
+
+	return cmd
+}
@@ -1,14 +1,26 @@
 package main
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/zhufuyi/goparser/commands"
 )
 
 func main() {
+	// cancel the running commands, e.g. go build, on Ctrl-C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// restore the default behavior after the first signal, so that a second one kills the process
+		<-ctx.Done()
+		stop()
+	}()
+
 	rootCMD := commands.NewRootCMD()
-	if err := rootCMD.Execute(); err != nil {
+	if err := rootCMD.ExecuteContext(ctx); err != nil {
 		rootCMD.PrintErrln("Error:", err)
 		os.Exit(1)
 	}
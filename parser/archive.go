@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/macho"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// magic numbers of the archive (.a) and the object file written by the go compiler
+var (
+	archiveMagic  = []byte("!<arch>\n")
+	goObjectMagic = []byte("go object ")
+)
+
+// IsObjectFile whether the file is an archive or an object file that has not been linked,
+// e.g. the .a files in the build cache, the outputs of "go build -o x.a" and "go tool compile",
+// they have no build info, the packages are aggregated from the symbol names.
+func IsObjectFile(file string) bool {
+	f, err := os.Open(file)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	header := make([]byte, 16)
+	n, _ := io.ReadFull(f, header)
+	header = header[:n]
+	if bytes.HasPrefix(header, archiveMagic) || bytes.HasPrefix(header, goObjectMagic) {
+		return true
+	}
+
+	if ef, err := elf.NewFile(f); err == nil {
+		return ef.Type == elf.ET_REL
+	}
+	if mf, err := macho.NewFile(f); err == nil {
+		return mf.Type == macho.TypeObj
+	}
+	return false
+}
+
+// the member of a c-archive that contains the go code, the other members are the objects of the C code
+const goObjectMember = "go.o"
+
+// size and terminator of the header of an archive member
+const (
+	archiveHeaderSize = 60
+	archiveHeaderEnd  = "`\n"
+)
+
+var (
+	errNotArchive       = errors.New("not an archive file")
+	errNoArchiveMember  = errors.New("archive member not found")
+	errMalformedArchive = errors.New("malformed archive file")
+)
+
+// archiveMember get the content of the named member of the archive, return errNotArchive if
+// the file is not an archive, errNoArchiveMember if the member is not found.
+func archiveMember(r io.ReaderAt, name string) (*io.SectionReader, error) {
+	magic := make([]byte, len(archiveMagic))
+	if _, err := r.ReadAt(magic, 0); err != nil || !bytes.Equal(magic, archiveMagic) {
+		return nil, errNotArchive
+	}
+
+	header := make([]byte, archiveHeaderSize)
+	off := int64(len(archiveMagic))
+	for {
+		if _, err := r.ReadAt(header, off); err != nil {
+			if errors.Is(err, io.EOF) && isEOF(r, off) {
+				return nil, errNoArchiveMember
+			}
+			return nil, fmt.Errorf("%w: truncated header at offset %d", errMalformedArchive, off)
+		}
+		if string(header[58:60]) != archiveHeaderEnd {
+			return nil, fmt.Errorf("%w: bad header at offset %d", errMalformedArchive, off)
+		}
+		size, err := strconv.ParseInt(strings.TrimSpace(string(header[48:58])), 10, 64)
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("%w: bad member size at offset %d", errMalformedArchive, off)
+		}
+
+		dataOff := off + archiveHeaderSize
+		memberName := strings.TrimSpace(string(header[:16]))
+		nameSize := int64(0)
+		// BSD archives store the long name at the beginning of the data, e.g. "#1/20"
+		if strings.HasPrefix(memberName, "#1/") {
+			nameSize, err = strconv.ParseInt(memberName[3:], 10, 64)
+			if err != nil || nameSize < 0 || nameSize > size {
+				return nil, fmt.Errorf("%w: bad member name length at offset %d", errMalformedArchive, off)
+			}
+			buf := make([]byte, nameSize)
+			if _, err = r.ReadAt(buf, dataOff); err != nil {
+				return nil, fmt.Errorf("%w: truncated member name at offset %d", errMalformedArchive, off)
+			}
+			memberName = strings.TrimRight(string(buf), "\x00")
+		}
+		// GNU archives terminate the name with "/"
+		if strings.TrimSuffix(memberName, "/") == name {
+			return io.NewSectionReader(r, dataOff+nameSize, size-nameSize), nil
+		}
+
+		// the data is aligned to an even offset
+		next := dataOff + size + size%2
+		if next <= off {
+			return nil, fmt.Errorf("%w: bad member size at offset %d", errMalformedArchive, off)
+		}
+		off = next
+	}
+}
+
+// whether off is the end of the file, i.e. there is no more member
+func isEOF(r io.ReaderAt, off int64) bool {
+	_, err := r.ReadAt(make([]byte, 1), off)
+	return errors.Is(err, io.EOF)
+}
@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// archive header: name(16) mtime(12) uid(6) gid(6) mode(8) size(10) end(2)
+func arHeader(name string, size string) string {
+	return fmt.Sprintf("%-16s%-12s%-6s%-6s%-8s%-10s`\n", name, "0", "0", "0", "644", size)
+}
+
+func TestArchiveMember(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string // content of go.o
+		wantErr error
+	}{
+		{
+			name: "gnu",
+			data: "!<arch>\n" + arHeader("000000.o/", "3") + "abc\n" + arHeader("go.o/", "4") + "gogo",
+			want: "gogo",
+		},
+		{
+			name: "bsd long name",
+			data: "!<arch>\n" + arHeader("#1/8", "12") + "go.o\x00\x00\x00\x00data",
+			want: "data",
+		},
+		{
+			name:    "not found",
+			data:    "!<arch>\n" + arHeader("000000.o/", "2") + "ab",
+			wantErr: errNoArchiveMember,
+		},
+		{
+			name:    "not archive",
+			data:    "\x7fELF",
+			wantErr: errNotArchive,
+		},
+		{
+			name:    "truncated header",
+			data:    "!<arch>\n" + arHeader("000000.o/", "2")[:30],
+			wantErr: errMalformedArchive,
+		},
+		{
+			name:    "bad header end",
+			data:    "!<arch>\n" + arHeader("000000.o/", "2")[:58] + "xx" + "ab",
+			wantErr: errMalformedArchive,
+		},
+		{
+			name:    "negative size",
+			data:    "!<arch>\n" + arHeader("000000.o/", "-60") + arHeader("000000.o/", "-60"),
+			wantErr: errMalformedArchive,
+		},
+		{
+			name:    "negative bsd name length",
+			data:    "!<arch>\n" + arHeader("#1/-5", "10") + "0123456789",
+			wantErr: errMalformedArchive,
+		},
+		{
+			name:    "bsd name longer than member",
+			data:    "!<arch>\n" + arHeader("#1/20", "4") + "go.o",
+			wantErr: errMalformedArchive,
+		},
+		{
+			name:    "invalid size",
+			data:    "!<arch>\n" + arHeader("go.o/", "x") + "gogo",
+			wantErr: errMalformedArchive,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			member, err := archiveMember(bytes.NewReader([]byte(tt.data)), goObjectMember)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("archiveMember() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := io.ReadAll(member)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("archiveMember() = %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
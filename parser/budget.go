@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Budget size limits of the binary file, a zero value means no limit, example:
+//
+//	{
+//	  "maxFileSize": 20971520,
+//	  "maxSymbolSize": 1048576,
+//	  "packages": {
+//	    "github.com/foo/bar": {"maxSize": 3145728},
+//	    "golang.org/x/net": {"maxPercentage": 5}
+//	  },
+//	  "symbols": {"main.bigTable": 65536}
+//	}
+type Budget struct {
+	MaxFileSize   int                   `json:"maxFileSize"`   // size of the binary file on disk
+	MaxTotalSize  int                   `json:"maxTotalSize"`  // sum of symbol sizes
+	MaxSymbolSize int                   `json:"maxSymbolSize"` // size of any single symbol
+	Packages      map[string]*PkgBudget `json:"packages"`      // key is package name
+	Symbols       map[string]int        `json:"symbols"`       // key is symbol name, value is max size
+}
+
+// PkgBudget size limits of a package
+type PkgBudget struct {
+	MaxSize       int     `json:"maxSize"`
+	MaxPercentage float32 `json:"maxPercentage"` // percentage of the total size
+}
+
+// BudgetViolation a size limit exceeded
+type BudgetViolation struct {
+	Kind  string `json:"kind"` // file, total, package or symbol
+	Name  string `json:"name"`
+	Size  string `json:"size"`
+	Limit string `json:"limit"`
+}
+
+func (v *BudgetViolation) String() string {
+	if v.Name == "" {
+		return fmt.Sprintf("%s size %s exceeds the limit %s", v.Kind, v.Size, v.Limit)
+	}
+	return fmt.Sprintf("%s %s size %s exceeds the limit %s", v.Kind, v.Name, v.Size, v.Limit)
+}
+
+// LoadBudget load budget from json file.
+func LoadBudget(file string) (*Budget, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := &Budget{}
+	err = json.Unmarshal(data, budget)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal budget file %s failed: %v", file, err)
+	}
+	return budget, nil
+}
+
+// CheckBudget check the binary file against the budget, return the violations.
+func (bp *BinaryParser) CheckBudget(budget *Budget) []*BudgetViolation {
+	var violations []*BudgetViolation
+
+	if budget.MaxFileSize > 0 && bp.FileSize > budget.MaxFileSize {
+		violations = append(violations, newBytesViolation("file", "", bp.FileSize, budget.MaxFileSize))
+	}
+	if budget.MaxTotalSize > 0 && bp.TotalSize > budget.MaxTotalSize {
+		violations = append(violations, newBytesViolation("total", "", bp.TotalSize, budget.MaxTotalSize))
+	}
+
+	for _, info := range bp.PkgInfos {
+		pkgName := strings.TrimRight(info.PkgName, "/")
+		pb, ok := budget.Packages[pkgName]
+		if !ok || pb == nil {
+			continue
+		}
+		if pb.MaxSize > 0 && info.Size > pb.MaxSize {
+			violations = append(violations, newBytesViolation("package", pkgName, info.Size, pb.MaxSize))
+		}
+		if pb.MaxPercentage > 0 && info.SizePercentage > pb.MaxPercentage {
+			violations = append(violations, &BudgetViolation{
+				Kind:  "package",
+				Name:  pkgName,
+				Size:  fmt.Sprintf("%.2f%%", info.SizePercentage),
+				Limit: fmt.Sprintf("%.2f%%", pb.MaxPercentage),
+			})
+		}
+	}
+
+	for _, nm := range bp.NmParsers {
+		limit, ok := budget.Symbols[nm.Symbol]
+		if !ok {
+			limit = budget.MaxSymbolSize
+		}
+		if limit > 0 && nm.Size > limit {
+			violations = append(violations, newBytesViolation("symbol", nm.Symbol, nm.Size, limit))
+		}
+	}
+
+	return violations
+}
+
+func newBytesViolation(kind string, name string, size int, limit int) *BudgetViolation {
+	return &BudgetViolation{
+		Kind:  kind,
+		Name:  name,
+		Size:  fmt.Sprintf("%d bytes", size),
+		Limit: fmt.Sprintf("%d bytes", limit),
+	}
+}
@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// BuildOptions options of building a binary file for analysis
+type BuildOptions struct {
+	Dir      string   // source directory of the module, default is the current directory
+	Pkg      string   // main package to build, e.g. ./cmd/server
+	GOOS     string   // target operating system, default is the host
+	GOARCH   string   // target architecture, default is the host
+	Tags     []string // build tags
+	TrimPath bool     // remove file system paths from the binary file
+	LDFlags  string   // flags passed to the linker, -s and -w are removed to retain symbols
+
+	Runner CommandRunner // runner of the go and git commands, default is DefaultCommandRunner
+}
+
+// Platform the target platform of the build, e.g. linux/amd64
+func (o *BuildOptions) Platform() string {
+	goos, goarch := o.GOOS, o.GOARCH
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	if goarch == "" {
+		goarch = runtime.GOARCH
+	}
+	return goos + "/" + goarch
+}
+
+// BuildBinary build the main package into outDir and return the path of the binary file,
+// the symbols are always retained so that the binary file can be analyzed.
+func BuildBinary(ctx context.Context, opts *BuildOptions, outDir string) (string, error) {
+	if opts.Pkg == "" {
+		return "", fmt.Errorf("build package is empty")
+	}
+
+	goos, goarch, _ := strings.Cut(opts.Platform(), "/")
+	name := "app_" + goos + "_" + goarch
+	if goos == "windows" {
+		name += ".exe"
+	}
+	output, err := filepath.Abs(filepath.Join(outDir, name))
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"build", "-o", output}
+	if opts.TrimPath {
+		args = append(args, "-trimpath")
+	}
+	if len(opts.Tags) > 0 {
+		args = append(args, "-tags", strings.Join(opts.Tags, ","))
+	}
+	if ldflags := RetainSymbolsLDFlags(opts.LDFlags); ldflags != "" {
+		args = append(args, "-ldflags", ldflags)
+	}
+	args = append(args, opts.Pkg)
+
+	var env []string
+	if opts.GOOS != "" {
+		env = append(env, "GOOS="+opts.GOOS)
+	}
+	if opts.GOARCH != "" {
+		env = append(env, "GOARCH="+opts.GOARCH)
+	}
+
+	cmd := &Command{Name: "go", Args: args, Dir: opts.Dir, Env: env}
+	_, err = runnerOrDefault(opts.Runner).Run(ctx, cmd)
+	if err != nil {
+		return "", fmt.Errorf("build %s for %s failed: %v", opts.Pkg, opts.Platform(), err)
+	}
+	if _, err = os.Stat(output); err != nil {
+		return "", err
+	}
+
+	return output, nil
+}
+
+// RetainSymbolsLDFlags remove the linker flags that strip the symbol table and debug information,
+// the other flags are kept as is, including the quoted values, e.g. -X 'main.version=1.0 beta'.
+func RetainSymbolsLDFlags(ldflags string) string {
+	var flags []string
+	for _, flag := range splitLDFlags(ldflags) {
+		name, value, _ := strings.Cut(strings.TrimLeft(flag, "-"), "=")
+		if strings.HasPrefix(flag, "-") && (name == "s" || name == "w") && value != "false" {
+			continue
+		}
+		flags = append(flags, flag)
+	}
+	return strings.Join(flags, " ")
+}
+
+// split the linker flags by spaces like the go command, the quoted parts are not split
+// and the quotes are retained.
+func splitLDFlags(ldflags string) []string {
+	var flags []string
+	start, quote := -1, byte(0)
+	for i := 0; i < len(ldflags); i++ {
+		c := ldflags[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			if start < 0 {
+				start = i
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if start >= 0 {
+				flags = append(flags, ldflags[start:i])
+				start = -1
+			}
+		default:
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+	if start >= 0 {
+		flags = append(flags, ldflags[start:])
+	}
+	return flags
+}
@@ -0,0 +1,24 @@
+package parser
+
+import "testing"
+
+func TestRetainSymbolsLDFlags(t *testing.T) {
+	tests := map[string]string{
+		"":                                  "",
+		"-s -w":                             "",
+		"-s -w -X main.version=1.0":         "-X main.version=1.0",
+		"-X 'main.v=a b' -s":                "-X 'main.v=a b'",
+		`-X "main.v=a -s b" -w`:             `-X "main.v=a -s b"`,
+		"-X=main.v='-s -w' -buildid=":       "-X=main.v='-s -w' -buildid=",
+		"-s=false -w=true":                  "-s=false",
+		"  -linkmode external\t-s  ":        "-linkmode external",
+		"-extldflags '-static -s'":          "-extldflags '-static -s'",
+		"-X 'main.v=unterminated -s":        "-X 'main.v=unterminated -s",
+		"-X main.a=1 -X 'main.b=x y' -w -s": "-X main.a=1 -X 'main.b=x y'",
+	}
+	for ldflags, want := range tests {
+		if got := RetainSymbolsLDFlags(ldflags); got != want {
+			t.Errorf("RetainSymbolsLDFlags(%q) = %q, want %q", ldflags, got, want)
+		}
+	}
+}
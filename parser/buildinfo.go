@@ -0,0 +1,189 @@
+package parser
+
+import (
+	"bytes"
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/macho"
+	"encoding/binary"
+	"os"
+	"runtime/debug"
+	"strings"
+)
+
+var buildInfoMagic = []byte("\xff Go buildinf:")
+
+// BuildInfo build information embedded in the binary file
+type BuildInfo struct {
+	GoVersion string          `json:"goVersion"`
+	Path      string          `json:"path"` // main package path
+	Main      *Module         `json:"main"` // main module
+	Deps      []*Module       `json:"deps"`
+	Settings  []*BuildSetting `json:"settings"`
+}
+
+// Module a module included in the build
+type Module struct {
+	Path    string  `json:"path"`
+	Version string  `json:"version"`
+	Sum     string  `json:"sum,omitempty"`
+	Replace *Module `json:"replace,omitempty"` // replaced by this module
+}
+
+// BuildSetting a key-value pair of build setting, e.g. GOOS=linux
+type BuildSetting struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// GetBuildInfo read the build information embedded in the binary file.
+func GetBuildInfo(file string) (*BuildInfo, error) {
+	bi, err := buildinfo.ReadFile(file)
+	if err != nil {
+		// the build info of a c-archive is in the member go.o
+		var ok bool
+		bi, ok = readArchiveBuildInfo(file)
+		if !ok {
+			return nil, err
+		}
+	}
+
+	info := &BuildInfo{
+		GoVersion: bi.GoVersion,
+		Path:      bi.Path,
+	}
+	if bi.Main.Path != "" {
+		info.Main = newModule(&bi.Main)
+	}
+	for _, dep := range bi.Deps {
+		info.Deps = append(info.Deps, newModule(dep))
+	}
+	for _, s := range bi.Settings {
+		info.Settings = append(info.Settings, &BuildSetting{Key: s.Key, Value: s.Value})
+	}
+
+	return info, nil
+}
+
+// Setting get the value of a build setting, return empty string if not found.
+func (bi *BuildInfo) Setting(key string) string {
+	for _, s := range bi.Settings {
+		if s.Key == key {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+// PkgInfos get the main module and dependencies, the name of the main module
+// ends with "/", filter by grep if it is not empty.
+func (bi *BuildInfo) PkgInfos(grep string) []*PkgInfo {
+	if bi == nil {
+		return nil
+	}
+
+	var pkgInfos []*PkgInfo
+	for i, m := range append([]*Module{bi.Main}, bi.Deps...) {
+		if m == nil {
+			continue
+		}
+		pkgInfo := &PkgInfo{
+			PkgName: m.Path,
+			Version: m.Version,
+			Sum:     m.Sum,
+			// a dependency in development is also the module being built
+			IsMod: i == 0 || m.Version == "(devel)",
+		}
+		// the symbols of a replaced module still use the original module path
+		if m.Replace != nil {
+			pkgInfo.ReplacePath = m.Replace.Path
+			pkgInfo.ReplaceVersion = m.Replace.Version
+			if m.Replace.Sum != "" {
+				pkgInfo.Sum = m.Replace.Sum
+			}
+		}
+		if grep != "" && !strings.Contains(pkgInfo.PkgName, grep) && !strings.Contains(pkgInfo.ReplacePath, grep) {
+			continue
+		}
+		if pkgInfo.IsMod {
+			pkgInfo.PkgName += "/"
+		}
+		pkgInfos = append(pkgInfos, pkgInfo)
+	}
+	return pkgInfos
+}
+
+// the go.o member of a c-archive is a relocatable object without segments, which can
+// not be read by debug/buildinfo, so the build info section is decoded directly.
+func readArchiveBuildInfo(file string) (*debug.BuildInfo, bool) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	member, err := archiveMember(f, goObjectMember)
+	if err != nil {
+		return nil, false
+	}
+
+	var data []byte
+	if ef, err := elf.NewFile(member); err == nil {
+		if sec := ef.Section(".go.buildinfo"); sec != nil {
+			data, _ = sec.Data()
+		}
+	} else if mf, err := macho.NewFile(member); err == nil {
+		if sec := mf.Section("__go_buildinfo"); sec != nil {
+			data, _ = sec.Data()
+		}
+	}
+
+	return decodeBuildInfo(data)
+}
+
+// decode the build info section, the header is 32 bytes, followed by the go version and
+// the module information which are stored inline as varint length-prefixed strings.
+func decodeBuildInfo(data []byte) (*debug.BuildInfo, bool) {
+	const headerSize = 32
+	const flagsVersionInl = 0x2
+	if len(data) < headerSize || !bytes.HasPrefix(data, buildInfoMagic) || data[15]&flagsVersionInl == 0 {
+		return nil, false
+	}
+
+	data = data[headerSize:]
+	goVersion, data := readVarintString(data)
+	modInfo, _ := readVarintString(data)
+	// the module information is wrapped by 16 bytes sentinels
+	if len(modInfo) >= 33 && modInfo[len(modInfo)-17] == '\n' {
+		modInfo = modInfo[16 : len(modInfo)-16]
+	} else {
+		modInfo = ""
+	}
+
+	bi, err := debug.ParseBuildInfo(modInfo)
+	if err != nil {
+		return nil, false
+	}
+	bi.GoVersion = goVersion
+	return bi, true
+}
+
+func readVarintString(data []byte) (string, []byte) {
+	n, size := binary.Uvarint(data)
+	if size <= 0 || n > uint64(len(data)-size) {
+		return "", nil
+	}
+	return string(data[size : size+int(n)]), data[size+int(n):]
+}
+
+func newModule(m *debug.Module) *Module {
+	module := &Module{
+		Path:    m.Path,
+		Version: m.Version,
+		Sum:     m.Sum,
+	}
+	if m.Replace != nil {
+		module.Replace = newModule(m.Replace)
+	}
+	return module
+}
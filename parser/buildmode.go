@@ -0,0 +1,313 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+)
+
+// build modes of the binary file, the same as the values of "go build -buildmode"
+const (
+	BuildModeExe      = "exe"
+	BuildModeCShared  = "c-shared"
+	BuildModeCArchive = "c-archive"
+	BuildModePlugin   = "plugin"
+	BuildModeArchive  = "archive" // archive or object file without build info
+)
+
+// groups of the build mode breakdown
+const (
+	GroupExport  = "exported API"
+	GroupModule  = "main module"
+	GroupDep     = "dependencies"
+	GroupStd     = "standard library"
+	GroupRuntime = "go runtime"
+	GroupOther   = "other"
+)
+
+// prefix of the go function generated by cgo for an //export function, e.g. _cgoexp_1b993489125c_Add
+const cgoExportPrefix = "_cgoexp_"
+
+// ExportInfo a function exported by //export or a symbol of the main package that can be looked up in a plugin
+type ExportInfo struct {
+	Name  string `json:"name"`
+	IsCgo bool   `json:"isCgo"` // exported to C by //export
+	Lines int    `json:"lines"` // the go function, the cgo wrapper and the C function
+	Size  int    `json:"size"`
+}
+
+// BreakdownInfo the size of a group of symbols, e.g. the go runtime and the exported API
+type BreakdownInfo struct {
+	Group          string  `json:"group"`
+	Lines          int     `json:"lines"`
+	Size           int     `json:"size"`
+	SizePercentage float32 `json:"sizePercentage"`
+}
+
+// GetBuildMode get the build mode from the build info, if there is no build info the file
+// is an archive or object file.
+func GetBuildMode(bi *BuildInfo) string {
+	if bi == nil {
+		return BuildModeArchive
+	}
+	if mode := bi.Setting("-buildmode"); mode != "" {
+		return mode
+	}
+	return BuildModeExe
+}
+
+// IsSharedBuildMode whether the binary file is a library loaded by another program.
+func IsSharedBuildMode(mode string) bool {
+	return mode == BuildModeCShared || mode == BuildModeCArchive || mode == BuildModePlugin
+}
+
+// the binary built with dynamic linking, e.g. a plugin, contains a local alias at the same address
+// for each global function, e.g. local.strings.ToUpper, they are removed to avoid double counting.
+func dropLocalAliases(nmParsers []*NmParser) []*NmParser {
+	addrs := make(map[string]string, len(nmParsers))
+	for _, nm := range nmParsers {
+		if !strings.HasPrefix(nm.Symbol, "local.") {
+			addrs[nm.Symbol] = nm.Address
+		}
+	}
+
+	result := nmParsers[:0]
+	for _, nm := range nmParsers {
+		if name := strings.TrimPrefix(nm.Symbol, "local."); name != nm.Symbol {
+			if addr, ok := addrs[name]; ok && addr == nm.Address {
+				continue
+			}
+		}
+		result = append(result, nm)
+	}
+	return result
+}
+
+// Exports get the functions exported by //export, and the exported symbols of the main package
+// of a plugin, sorted by size.
+func (bp *BinaryParser) Exports() []*ExportInfo {
+	exportMap := bp.exportSymbols()
+	infoMap := make(map[string]*ExportInfo)
+	for _, nm := range bp.NmParsers {
+		name, ok := exportMap[nm.Symbol]
+		if !ok {
+			continue
+		}
+		info, ok := infoMap[name]
+		if !ok {
+			info = &ExportInfo{Name: name}
+			infoMap[name] = info
+		}
+		if strings.HasPrefix(nm.Symbol, cgoExportPrefix) {
+			info.IsCgo = true
+		}
+		info.Lines++
+		info.Size += nm.Size
+	}
+
+	exports := make([]*ExportInfo, 0, len(infoMap))
+	for _, info := range infoMap {
+		exports = append(exports, info)
+	}
+	sort.Slice(exports, func(i, j int) bool {
+		if exports[i].Size != exports[j].Size {
+			return exports[i].Size > exports[j].Size
+		}
+		return exports[i].Name < exports[j].Name
+	})
+	return exports
+}
+
+// exportSymbols map the symbols of the exported API to the exported name, an //export function
+// Add consists of the C function Add, the cgo wrapper _cgoexp_<hash>_Add and the go function main.Add.
+func (bp *BinaryParser) exportSymbols() map[string]string {
+	mainPkgs := map[string]bool{"main": true}
+	if bp.BuildInfo != nil && bp.BuildInfo.Path != "" {
+		// the main package of a plugin is named by the plugin path
+		mainPkgs[bp.BuildInfo.Path] = true
+	}
+	isMainPkg := func(pkgPath string) bool {
+		return mainPkgs[pkgPath] || strings.HasPrefix(pkgPath, "plugin/unnamed-")
+	}
+
+	cgoExports := make(map[string]bool)
+	for _, nm := range bp.NmParsers {
+		if !strings.HasPrefix(nm.Symbol, cgoExportPrefix) {
+			continue
+		}
+		// _cgoexp_<hash>_<name>, the hash does not contain "_"
+		_, name, ok := strings.Cut(nm.Symbol[len(cgoExportPrefix):], "_")
+		if ok && name != "" {
+			cgoExports[name] = true
+		}
+	}
+
+	exportMap := make(map[string]string)
+	for _, nm := range bp.NmParsers {
+		switch {
+		case strings.HasPrefix(nm.Symbol, cgoExportPrefix):
+			_, name, _ := strings.Cut(nm.Symbol[len(cgoExportPrefix):], "_")
+			if cgoExports[name] {
+				exportMap[nm.Symbol] = name
+			}
+		case cgoExports[nm.Symbol]:
+			exportMap[nm.Symbol] = nm.Symbol
+		case nm.PkgPath != "" && isMainPkg(nm.PkgPath):
+			name := strings.TrimPrefix(nm.Symbol, nm.PkgPath+".")
+			if cgoExports[name] || (bp.BuildMode == BuildModePlugin && isExportedName(name)) {
+				exportMap[nm.Symbol] = name
+			}
+		}
+	}
+	return exportMap
+}
+
+// a plugin can only look up the exported functions and variables of the main package
+func isExportedName(name string) bool {
+	if strings.ContainsAny(name, ".[") {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
+
+// Breakdown get the size of the exported API, main module, dependencies, standard library
+// and go runtime, each symbol is counted in exactly one group.
+func (bp *BinaryParser) Breakdown() []*BreakdownInfo {
+	exportMap := bp.exportSymbols()
+	modules := make(map[string]*PkgInfo, len(bp.PkgInfos))
+	for _, info := range bp.PkgInfos {
+		modules[strings.TrimRight(info.PkgName, "/")] = info
+	}
+
+	groups := []string{GroupExport, GroupModule, GroupDep, GroupStd, GroupRuntime, GroupOther}
+	infoMap := make(map[string]*BreakdownInfo, len(groups))
+	for _, group := range groups {
+		infoMap[group] = &BreakdownInfo{Group: group}
+	}
+	for _, nm := range bp.NmParsers {
+		var group string
+		module := modules[nm.Module]
+		switch {
+		case exportMap[nm.Symbol] != "":
+			group = GroupExport
+		case isRuntimeSymbol(nm):
+			group = GroupRuntime
+		case module != nil && module.IsMod:
+			group = GroupModule
+		case module != nil && module.IsStd, nm.Module == "" && IsStdPkgPath(nm.PkgPath):
+			group = GroupStd
+		case module != nil && !module.IsOther:
+			group = GroupDep
+		default:
+			group = GroupOther
+		}
+		info := infoMap[group]
+		info.Lines++
+		info.Size += nm.Size
+	}
+
+	breakdown := make([]*BreakdownInfo, 0, len(groups))
+	for _, group := range groups {
+		info := infoMap[group]
+		if bp.TotalSize > 0 {
+			info.SizePercentage = float32(info.Size) / float32(bp.TotalSize) * 100
+		}
+		breakdown = append(breakdown, info)
+	}
+	return breakdown
+}
+
+// the symbols of the runtime packages, the C code of runtime/cgo and the metadata generated by the linker
+func isRuntimeSymbol(nm *NmParser) bool {
+	pkgPath := nm.PkgPath
+	if pkgPath == "runtime" || strings.HasPrefix(pkgPath, "runtime/") || strings.HasPrefix(pkgPath, "internal/") {
+		return true
+	}
+	if pkgPath == "" {
+		// the type names are hashed when dynamic linking, e.g. type:1qftIZdl
+		switch nm.Kind {
+		case KindType, KindItab, KindString, KindRuntime:
+			return true
+		}
+		for _, prefix := range []string{"x_cgo_", "_cgo_", "crosscall", "_rt0_"} {
+			if strings.HasPrefix(nm.Symbol, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *TextRenderer) writeBuildMode(w io.Writer, report *Report) {
+	bmMaxWidth := []int{r.MaxWidth, 11, 11, 15}
+	for i := 0; i < len(bmMaxWidth); i++ {
+		bmMaxWidth[i] += 4
+	}
+
+	title := fmt.Sprintf("%-*s%-*s%-*s%-*s",
+		bmMaxWidth[0], "Group",
+		bmMaxWidth[1], "Count Rows",
+		bmMaxWidth[2], "Size(bytes)",
+		bmMaxWidth[3], "Percentage(size)")
+	separators := strings.Repeat("-", len(title)-4)
+
+	fmt.Fprintf(w, "\nparse build mode results:\nbuild mode: %s, exported symbols: %s\n",
+		r.paint(color.FgHiGreen, report.BuildMode),
+		r.paint(color.FgHiCyan, strconv.Itoa(len(report.Exports))),
+	)
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	fmt.Fprintln(w, r.paint(color.FgHiCyan, title))
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	for _, info := range report.Breakdown {
+		fmt.Fprintf(w, "%-*s%-*s%-*s%-*s\n",
+			bmMaxWidth[0], info.Group,
+			bmMaxWidth[1], strconv.Itoa(info.Lines),
+			bmMaxWidth[2], strconv.Itoa(info.Size),
+			bmMaxWidth[3], fmt.Sprintf("%.2f%%", info.SizePercentage),
+		)
+	}
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	if len(report.Exports) == 0 {
+		return
+	}
+
+	topN := r.limit()
+	exports := report.Exports
+	if len(exports) > topN {
+		exports = exports[:topN]
+	}
+	title = fmt.Sprintf("%-*s%-*s%-*s%-*s",
+		bmMaxWidth[0], "Exported Symbol",
+		bmMaxWidth[1], "Count Rows",
+		bmMaxWidth[2], "Size(bytes)",
+		bmMaxWidth[3], "Export")
+	fmt.Fprintf(w, "\nexported symbols, show top %s rows:\n", r.paint(color.FgHiMagenta, strconv.Itoa(len(exports))))
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	fmt.Fprintln(w, r.paint(color.FgHiCyan, title))
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	for _, info := range exports {
+		name := info.Name
+		if len(name) > bmMaxWidth[0] {
+			size := bmMaxWidth[0] - 29
+			name = name[:20] + " ... " + name[len(name)-size:]
+		}
+		export := "plugin"
+		if info.IsCgo {
+			export = "//export"
+		}
+		fmt.Fprintf(w, "%-*s%-*s%-*s%-*s\n",
+			bmMaxWidth[0], name,
+			bmMaxWidth[1], strconv.Itoa(info.Lines),
+			bmMaxWidth[2], strconv.Itoa(info.Size),
+			bmMaxWidth[3], export,
+		)
+	}
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+}
@@ -0,0 +1,215 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// diff status
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffGrown   = "grown"
+	DiffShrunk  = "shrunk"
+)
+
+// SizeDelta size change of a symbol or package between two binary files
+type SizeDelta struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	OldSize    int     `json:"oldSize"`
+	NewSize    int     `json:"newSize"`
+	Delta      int     `json:"delta"`
+	Percentage float32 `json:"percentage"` // delta / old size
+}
+
+// BinaryDiff the differences between two binary files, unchanged symbols and packages are not included
+type BinaryDiff struct {
+	OldFile      string       `json:"oldFile"`
+	NewFile      string       `json:"newFile"`
+	OldTotalSize int          `json:"oldTotalSize"`
+	NewTotalSize int          `json:"newTotalSize"`
+	Symbols      []*SizeDelta `json:"symbols"`
+	Packages     []*SizeDelta `json:"packages"`
+	MaxWidth     int          `json:"-"`
+}
+
+// DiffBinaryParser compare two binary files, symbols are matched by symbol name and packages
+// are matched by package name, the results are sorted by the absolute value of the delta.
+func DiffBinaryParser(oldFile string, oldBP *BinaryParser, newFile string, newBP *BinaryParser) *BinaryDiff {
+	oldSymbols := make(map[string]int, len(oldBP.NmParsers))
+	for _, nm := range oldBP.NmParsers {
+		oldSymbols[nm.Symbol] += nm.Size
+	}
+	newSymbols := make(map[string]int, len(newBP.NmParsers))
+	for _, nm := range newBP.NmParsers {
+		newSymbols[nm.Symbol] += nm.Size
+	}
+
+	oldPkgs := make(map[string]int, len(oldBP.PkgInfos))
+	for _, info := range oldBP.PkgInfos {
+		oldPkgs[strings.TrimRight(info.PkgName, "/")] += info.Size
+	}
+	newPkgs := make(map[string]int, len(newBP.PkgInfos))
+	for _, info := range newBP.PkgInfos {
+		newPkgs[strings.TrimRight(info.PkgName, "/")] += info.Size
+	}
+
+	return &BinaryDiff{
+		OldFile:      oldFile,
+		NewFile:      newFile,
+		OldTotalSize: oldBP.TotalSize,
+		NewTotalSize: newBP.TotalSize,
+		Symbols:      diffSizes(oldSymbols, newSymbols),
+		Packages:     diffSizes(oldPkgs, newPkgs),
+		MaxWidth:     newBP.MaxWidth,
+	}
+}
+
+func diffSizes(oldSizes map[string]int, newSizes map[string]int) []*SizeDelta {
+	var deltas []*SizeDelta
+	for name, oldSize := range oldSizes {
+		newSize, ok := newSizes[name]
+		if !ok {
+			deltas = append(deltas, newSizeDelta(name, DiffRemoved, oldSize, 0))
+			continue
+		}
+		if newSize > oldSize {
+			deltas = append(deltas, newSizeDelta(name, DiffGrown, oldSize, newSize))
+		} else if newSize < oldSize {
+			deltas = append(deltas, newSizeDelta(name, DiffShrunk, oldSize, newSize))
+		}
+	}
+	for name, newSize := range newSizes {
+		if _, ok := oldSizes[name]; !ok {
+			deltas = append(deltas, newSizeDelta(name, DiffAdded, 0, newSize))
+		}
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		di, dj := abs(deltas[i].Delta), abs(deltas[j].Delta)
+		if di != dj {
+			return di > dj
+		}
+		return deltas[i].Name < deltas[j].Name
+	})
+
+	return deltas
+}
+
+func newSizeDelta(name string, status string, oldSize int, newSize int) *SizeDelta {
+	delta := &SizeDelta{
+		Name:    name,
+		Status:  status,
+		OldSize: oldSize,
+		NewSize: newSize,
+		Delta:   newSize - oldSize,
+	}
+	switch {
+	case oldSize > 0:
+		delta.Percentage = float32(delta.Delta) / float32(oldSize) * 100
+	case newSize > 0:
+		delta.Percentage = 100
+	}
+	return delta
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// PrintJSON print the differences in json format to the writer.
+func (d *BinaryDiff) PrintJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Print print the top N changed symbols and packages to the writer.
+func (d *BinaryDiff) Print(w io.Writer, topN int) {
+	totalDelta := d.NewTotalSize - d.OldTotalSize
+	fmt.Fprintf(w, "\ndiff binary file \"%s\" => \"%s\" results:\ntotal size: %s => %s bytes, delta: %s bytes\n",
+		d.OldFile, d.NewFile,
+		color.HiGreenString(strconv.Itoa(d.OldTotalSize)),
+		color.HiGreenString(strconv.Itoa(d.NewTotalSize)),
+		colorDelta(totalDelta, fmt.Sprintf("%+d", totalDelta)))
+
+	d.printDeltas(w, "Symbol", d.Symbols, topN)
+	d.printDeltas(w, "Package", d.Packages, topN)
+}
+
+func (d *BinaryDiff) printDeltas(w io.Writer, name string, deltas []*SizeDelta, topN int) {
+	maxWidth := []int{d.MaxWidth, 7, 11, 11, 11, 10}
+	for i := 0; i < len(maxWidth); i++ {
+		maxWidth[i] += 4
+	}
+
+	counts := map[string]int{}
+	for _, delta := range deltas {
+		counts[delta.Status]++
+	}
+	n := topN
+	if topN > len(deltas) {
+		n = len(deltas)
+	}
+	fmt.Fprintf(w, "\n%s changes: added %s, removed %s, grown %s, shrunk %s, show top %s rows:\n",
+		strings.ToLower(name),
+		color.HiCyanString(strconv.Itoa(counts[DiffAdded])),
+		color.HiCyanString(strconv.Itoa(counts[DiffRemoved])),
+		color.HiCyanString(strconv.Itoa(counts[DiffGrown])),
+		color.HiCyanString(strconv.Itoa(counts[DiffShrunk])),
+		color.HiMagentaString(strconv.Itoa(n)))
+
+	title := fmt.Sprintf("%-*s%-*s%-*s%-*s%-*s%-*s",
+		maxWidth[0], name,
+		maxWidth[1], "Status",
+		maxWidth[2], "Old(bytes)",
+		maxWidth[3], "New(bytes)",
+		maxWidth[4], "Delta",
+		maxWidth[5], "Delta(%)")
+	separators := strings.Repeat("-", len(title)-4)
+	fmt.Fprintln(w, color.HiBlackString(separators))
+	fmt.Fprintln(w, color.HiCyanString(title))
+	fmt.Fprintln(w, color.HiBlackString(separators))
+	if len(deltas) > topN {
+		deltas = deltas[:topN]
+	}
+	for _, delta := range deltas {
+		deltaName := delta.Name
+		if len(deltaName) >= maxWidth[0] {
+			size := maxWidth[0] - 29
+			deltaName = deltaName[:20] + " ... " + deltaName[len(deltaName)-size:]
+		}
+		fmt.Fprintf(w, "%-*s%-*s%-*s%-*s%s%s\n",
+			maxWidth[0], deltaName,
+			maxWidth[1], delta.Status,
+			maxWidth[2], strconv.Itoa(delta.OldSize),
+			maxWidth[3], strconv.Itoa(delta.NewSize),
+			colorDelta(delta.Delta, fmt.Sprintf("%-*s", maxWidth[4], fmt.Sprintf("%+d", delta.Delta))),
+			colorDelta(delta.Delta, fmt.Sprintf("%+.2f%%", delta.Percentage)))
+	}
+	if len(deltas) > 0 {
+		fmt.Fprintln(w, color.HiBlackString(separators))
+	}
+}
+
+func colorDelta(delta int, s string) string {
+	if delta > 0 {
+		return color.HiRedString(s)
+	} else if delta < 0 {
+		return color.HiGreenString(s)
+	}
+	return s
+}
@@ -1,63 +1,118 @@
 package parser
 
 import (
+	"bytes"
+	"context"
 	"errors"
-	"io"
+	"fmt"
+	"os"
 	"os/exec"
+	"strings"
+	"time"
 )
 
-func Exec(name string, args ...string) ([]byte, error) {
-	cmdName, err := exec.LookPath(name) // cmdName is absolute path
-	if err != nil {
-		return nil, err
-	}
-
-	cmd := exec.Command(cmdName, args...)
-	return getResult(cmd)
+// Command an external command to run
+type Command struct {
+	Name string
+	Args []string
+	Dir  string   // working directory, default is the current directory
+	Env  []string // extra environment variables, e.g. GOOS=linux
 }
 
-func getCmdReader(cmd *exec.Cmd) (io.ReadCloser, io.ReadCloser, error) {
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, nil, err
-	}
+func (c *Command) String() string {
+	return strings.Join(append([]string{c.Name}, c.Args...), " ")
+}
 
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		return nil, nil, err
-	}
+// CommandRunner run an external command and return its stdout, it can be replaced
+// to substitute fake toolchain output.
+type CommandRunner interface {
+	Run(ctx context.Context, cmd *Command) ([]byte, error)
+}
 
-	err = cmd.Start()
-	if err != nil {
-		return nil, nil, err
-	}
+// DefaultCommandRunner the runner used by Exec, ExecInDir, ExecWithEnv and their context variants,
+// and by the functions that are not given a runner
+var DefaultCommandRunner CommandRunner = &ExecRunner{}
 
-	return stdout, stderr, nil
+// ExecRunner run the command by os/exec, the command is killed when the context is done
+// or the timeout is reached.
+type ExecRunner struct {
+	Timeout time.Duration // no timeout if it is not greater than 0
 }
 
-func getResult(cmd *exec.Cmd) ([]byte, error) {
-	stdout, stderr, err := getCmdReader(cmd)
-	if err != nil {
-		return nil, err
+// Run run the command, stdout and stderr are drained concurrently, the stderr is
+// returned as the error if the command fails.
+func (r *ExecRunner) Run(ctx context.Context, c *Command) ([]byte, error) {
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
 	}
 
-	bytes, err := io.ReadAll(stdout)
+	cmdName, err := exec.LookPath(c.Name) // cmdName is absolute path
 	if err != nil {
 		return nil, err
 	}
 
-	bytesErr, err := io.ReadAll(stderr)
-	if err != nil {
-		return nil, err
+	cmd := exec.CommandContext(ctx, cmdName, c.Args...)
+	cmd.Dir = c.Dir
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
 	}
+	// do not wait forever for the pipes held by the grandchild processes after killed
+	cmd.WaitDelay = time.Second
 
-	err = cmd.Wait()
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err = cmd.Run()
 	if err != nil {
-		if len(bytesErr) != 0 {
-			return nil, errors.New(string(bytesErr))
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("%s: %w", c, ctxErr)
+		}
+		if stderr.Len() != 0 {
+			return nil, errors.New(stderr.String())
 		}
 		return nil, err
 	}
 
-	return bytes, nil
+	return stdout.Bytes(), nil
+}
+
+// Exec execute the command by DefaultCommandRunner.
+func Exec(name string, args ...string) ([]byte, error) {
+	return ExecContext(context.Background(), name, args...)
+}
+
+// ExecInDir execute the command in the specified directory.
+func ExecInDir(dir string, name string, args ...string) ([]byte, error) {
+	return ExecInDirContext(context.Background(), dir, name, args...)
+}
+
+// ExecWithEnv execute the command in the specified directory with extra environment variables.
+func ExecWithEnv(dir string, env []string, name string, args ...string) ([]byte, error) {
+	return ExecWithEnvContext(context.Background(), dir, env, name, args...)
+}
+
+// ExecContext execute the command, it is killed when the context is done.
+func ExecContext(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return DefaultCommandRunner.Run(ctx, &Command{Name: name, Args: args})
+}
+
+// ExecInDirContext execute the command in the specified directory, it is killed when the context is done.
+func ExecInDirContext(ctx context.Context, dir string, name string, args ...string) ([]byte, error) {
+	return DefaultCommandRunner.Run(ctx, &Command{Name: name, Args: args, Dir: dir})
+}
+
+// ExecWithEnvContext execute the command in the specified directory with extra environment variables,
+// it is killed when the context is done.
+func ExecWithEnvContext(ctx context.Context, dir string, env []string, name string, args ...string) ([]byte, error) {
+	return DefaultCommandRunner.Run(ctx, &Command{Name: name, Args: args, Dir: dir, Env: env})
+}
+
+// get the runner, DefaultCommandRunner is used if it is nil
+func runnerOrDefault(runner CommandRunner) CommandRunner {
+	if runner == nil {
+		return DefaultCommandRunner
+	}
+	return runner
 }
@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fakeRunner return the canned output of the command and record the commands that are run
+type fakeRunner struct {
+	outputs  map[string]string // command line => stdout
+	commands []*Command
+}
+
+func (r *fakeRunner) Run(ctx context.Context, cmd *Command) ([]byte, error) {
+	r.commands = append(r.commands, cmd)
+	out, ok := r.outputs[cmd.String()]
+	if !ok {
+		return nil, fmt.Errorf("unexpected command: %s", cmd)
+	}
+	return []byte(out), nil
+}
+
+func TestGetNmParsersByGoTool(t *testing.T) {
+	file := "/tmp/x.a"
+	runner := &fakeRunner{outputs: map[string]string{
+		"go tool nm -size " + file: file + "(_go_.o):\t  1a0        120 T github.com/foo/bar.(*Server).Run\n" +
+			file + "(_go_.o):\t  2b0         16 D github.com/foo/bar.version\n" +
+			file + "(_go_.o):\t    0          0 ? go:constinfo.github.com/foo/bar\n" +
+			"  3c0         40 T strings.ToUpper\n",
+	}}
+
+	nmParsers, err := getNmParsersByGoTool(context.Background(), runner, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*NmParser{
+		{Address: "1a0", Size: 120, Type: "T", Symbol: "github.com/foo/bar.(*Server).Run"},
+		{Address: "2b0", Size: 16, Type: "D", Symbol: "github.com/foo/bar.version"},
+		{Address: "3c0", Size: 40, Type: "T", Symbol: "strings.ToUpper"},
+	}
+	if !reflect.DeepEqual(nmParsers, want) {
+		for _, nm := range nmParsers {
+			t.Logf("%+v", nm)
+		}
+		t.Errorf("getNmParsersByGoTool() got %d symbols, want %d", len(nmParsers), len(want))
+	}
+}
+
+func TestGoListDeps(t *testing.T) {
+	runner := &fakeRunner{outputs: map[string]string{
+		"go list -deps -json ./cmd/app": `{"ImportPath": "errors", "Name": "errors", "Standard": true}
+{"ImportPath": "github.com/foo/bar", "Name": "bar", "Imports": ["errors"]}
+{"ImportPath": "example.com/app/cmd/app", "Name": "main", "Imports": ["github.com/foo/bar"]}
+`,
+	}}
+
+	pkgs, err := GoListDeps(context.Background(), "/src/app", "./cmd/app", WithCommandRunner(runner))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) != 3 || pkgs[2].ImportPath != "example.com/app/cmd/app" || !pkgs[0].Standard {
+		t.Errorf("GoListDeps() got %+v", pkgs)
+	}
+	if dir := runner.commands[0].Dir; dir != "/src/app" {
+		t.Errorf("go list is run in %q, want /src/app", dir)
+	}
+}
+
+func TestBuildBinary(t *testing.T) {
+	outDir := t.TempDir()
+	output := filepath.Join(outDir, "app_linux_arm64")
+	runner := &fakeRunner{outputs: map[string]string{
+		"go build -o " + output + " -trimpath -ldflags -X main.version=1.0 ./cmd/app": "",
+	}}
+	// the fake go build does not write the binary file
+	if err := os.WriteFile(output, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &BuildOptions{
+		Pkg:      "./cmd/app",
+		GOOS:     "linux",
+		GOARCH:   "arm64",
+		TrimPath: true,
+		LDFlags:  "-s -w -X main.version=1.0",
+		Runner:   runner,
+	}
+	file, err := BuildBinary(context.Background(), opts, outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file != output {
+		t.Errorf("BuildBinary() = %q, want %q", file, output)
+	}
+	if env := runner.commands[0].Env; !reflect.DeepEqual(env, []string{"GOOS=linux", "GOARCH=arm64"}) {
+		t.Errorf("go build env = %v", env)
+	}
+}
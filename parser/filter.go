@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Filter decide which symbols are kept, the filtered symbols are excluded from
+// both the symbol list and the package aggregation, a zero value keeps all symbols.
+type Filter struct {
+	Types         []string // nm type codes to keep, e.g. T, t, D, B, R, U
+	ExcludeTypes  []string // nm type codes to exclude
+	MinSize       int      // minimum size in bytes
+	MinPercentage float32  // minimum percentage of the total size
+
+	// patterns matched against symbol names and package paths, a name is kept if it
+	// matches any of the include patterns and none of the exclude patterns.
+	Include []*regexp.Regexp
+	Exclude []*regexp.Regexp
+}
+
+// ParsePatterns compile the patterns, a pattern with prefix "glob:" is a glob
+// pattern in which "*" matches any characters and "?" matches a single character,
+// otherwise it is a regular expression.
+func ParsePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var res []*regexp.Regexp
+	for _, pattern := range patterns {
+		expr := pattern
+		if strings.HasPrefix(pattern, "glob:") {
+			expr = globToRegexp(strings.TrimPrefix(pattern, "glob:"))
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
+
+// MatchName whether the symbol name or package path matches the include and exclude patterns.
+func (f *Filter) MatchName(name string) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Include) > 0 && !matchAny(f.Include, name) {
+		return false
+	}
+	return !matchAny(f.Exclude, name)
+}
+
+func matchAny(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match whether the symbol is kept.
+func (f *Filter) Match(nm *NmParser) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Types) > 0 && !containsStr(f.Types, nm.Type) {
+		return false
+	}
+	if containsStr(f.ExcludeTypes, nm.Type) {
+		return false
+	}
+	if nm.Size < f.MinSize || nm.SizePercentage < f.MinPercentage {
+		return false
+	}
+	return f.MatchName(nm.Symbol)
+}
+
+func (f *Filter) filterNmParsers(nmParsers []*NmParser) []*NmParser {
+	if f == nil {
+		return nmParsers
+	}
+	var result []*NmParser
+	for _, nm := range nmParsers {
+		if f.Match(nm) {
+			result = append(result, nm)
+		}
+	}
+	return result
+}
+
+func (f *Filter) filterPkgInfos(pkgInfos []*PkgInfo) []*PkgInfo {
+	if f == nil {
+		return pkgInfos
+	}
+	var result []*PkgInfo
+	for _, info := range pkgInfos {
+		if f.MatchName(strings.TrimRight(info.PkgName, "/")) {
+			result = append(result, info)
+		}
+	}
+	return result
+}
+
+func containsStr(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
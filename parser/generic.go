@@ -0,0 +1,185 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// GenericInfo the instantiations of a generic function or method
+type GenericInfo struct {
+	Name           string   `json:"name"`      // generic definition, type arguments are replaced with "..."
+	Instances      int      `json:"instances"` // number of shapes stamped out, the go.shape.* type arguments
+	Shapes         []string `json:"shapes"`
+	Dictionaries   int      `json:"dictionaries"` // number of dictionaries for the instantiations
+	Size           int      `json:"size"`
+	SizePercentage float32  `json:"sizePercentage"`
+}
+
+// SplitGenericSymbol split an instantiated generic symbol into the generic definition and the type
+// arguments, e.g. "pkg.(*List[go.shape.int]).Push" => "pkg.(*List[...]).Push", "go.shape.int",
+// the dictionary symbol "pkg..dict.Func[int]" => "pkg.Func[...]", "int", isDict is true.
+// ok is false if the symbol is not a generic instantiation.
+func SplitGenericSymbol(symbol string) (name string, typeArgs string, isDict bool, ok bool) {
+	if strings.HasPrefix(symbol, "type:") || strings.HasPrefix(symbol, "go:") {
+		return "", "", false, false
+	}
+	if strings.Contains(symbol, "..dict.") {
+		symbol = strings.Replace(symbol, "..dict.", ".", 1)
+		isDict = true
+	}
+
+	var sb strings.Builder
+	var args []string
+	depth, start := 0, 0
+	for i := 0; i < len(symbol); i++ {
+		switch symbol[i] {
+		case '[':
+			if depth == 0 {
+				sb.WriteString("[...]")
+				start = i + 1
+			}
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				args = append(args, symbol[start:i])
+			}
+		default:
+			if depth == 0 {
+				sb.WriteByte(symbol[i])
+			}
+		}
+	}
+	if len(args) == 0 || depth != 0 {
+		return "", "", false, false
+	}
+
+	return sb.String(), strings.Join(args, ";"), isDict, true
+}
+
+// GetGenericInfos group the instantiations of generic functions and methods by their
+// generic definition, sorted by the combined size. The dictionaries, the compiler metadata
+// and the concrete instantiations that call the shaped one are counted in the size of the
+// group, only the go.shape.* type arguments are counted as shapes.
+func GetGenericInfos(nmParsers []*NmParser, totalSize int) []*GenericInfo {
+	var genericInfos []*GenericInfo
+	genericInfoMap := make(map[string]*GenericInfo)
+	shapeMap := make(map[string]map[string]struct{})
+	for _, nm := range nmParsers {
+		symbol, isMeta := trimCompilerMetadata(nm.Symbol)
+		name, typeArgs, isDict, ok := SplitGenericSymbol(symbol)
+		if !ok {
+			continue
+		}
+		info, ok := genericInfoMap[name]
+		if !ok {
+			info = &GenericInfo{Name: name}
+			genericInfoMap[name] = info
+			shapeMap[name] = make(map[string]struct{})
+			genericInfos = append(genericInfos, info)
+		}
+		info.Size += nm.Size
+		if isDict {
+			info.Dictionaries++
+			continue
+		}
+		if isMeta || !strings.Contains(typeArgs, "go.shape.") {
+			continue
+		}
+		if _, ok := shapeMap[name][typeArgs]; !ok {
+			shapeMap[name][typeArgs] = struct{}{}
+			info.Shapes = append(info.Shapes, typeArgs)
+			info.Instances++
+		}
+	}
+
+	for _, info := range genericInfos {
+		sort.Strings(info.Shapes)
+		if totalSize > 0 {
+			info.SizePercentage = float32(info.Size) / float32(totalSize) * 100
+		}
+	}
+	sort.Slice(genericInfos, func(i, j int) bool {
+		if genericInfos[i].Size != genericInfos[j].Size {
+			return genericInfos[i].Size > genericInfos[j].Size
+		}
+		return genericInfos[i].Name < genericInfos[j].Name
+	})
+
+	return genericInfos
+}
+
+// remove the suffix of the metadata generated by the compiler for a function, e.g.
+// "pkg.Func[go.shape.int].stkobj<1>" => "pkg.Func[go.shape.int]", isMeta is true.
+func trimCompilerMetadata(symbol string) (string, bool) {
+	if !strings.HasSuffix(symbol, ">") {
+		return symbol, false
+	}
+	i := strings.LastIndexByte(symbol, '.')
+	if i < 0 || strings.ContainsAny(symbol[i:], "[]") || !strings.Contains(symbol[i:], "<") {
+		return symbol, false
+	}
+	return symbol[:i], true
+}
+
+func (r *TextRenderer) writeGenerics(w io.Writer, report *Report) {
+	geMaxWidth := []int{r.MaxWidth, 9, 12, 11, 15}
+	for i := 0; i < len(geMaxWidth); i++ {
+		geMaxWidth[i] += 4
+	}
+
+	genericInfos := report.Generics
+	topN := r.limit()
+	totalLine := len(genericInfos)
+	n := topN
+	if topN > totalLine {
+		n = totalLine
+	}
+	sumSize, sumInstances := 0, 0
+	for _, info := range genericInfos {
+		sumSize += info.Size
+		sumInstances += info.Instances
+	}
+
+	title := fmt.Sprintf("%-*s%-*s%-*s%-*s%-*s",
+		geMaxWidth[0], "Generic",
+		geMaxWidth[1], "Shapes",
+		geMaxWidth[2], "Dictionaries",
+		geMaxWidth[3], "Size(bytes)",
+		geMaxWidth[4], "Percentage(size)")
+	resultTip := "parse generic instantiation results:"
+	fmt.Fprintf(w, "\n%s\nsum size: %s bytes, instantiations: %s, total rows: %s, show top %s rows:\n",
+		resultTip,
+		r.paint(color.FgHiGreen, strconv.Itoa(sumSize)),
+		r.paint(color.FgHiGreen, strconv.Itoa(sumInstances)),
+		r.paint(color.FgHiCyan, strconv.Itoa(totalLine)),
+		r.paint(color.FgHiMagenta, strconv.Itoa(n)))
+	separators := strings.Repeat("-", len(title)-4)
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	fmt.Fprintln(w, r.paint(color.FgHiCyan, title))
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	if len(genericInfos) > topN {
+		genericInfos = genericInfos[:topN]
+	}
+	for _, info := range genericInfos {
+		name := info.Name
+		if len(name) >= geMaxWidth[0] {
+			size := geMaxWidth[0] - 29
+			name = name[:20] + " ... " + name[len(name)-size:]
+		}
+		fmt.Fprintf(w, "%-*s%-*s%-*s%-*s%-*s\n",
+			geMaxWidth[0], name,
+			geMaxWidth[1], strconv.Itoa(info.Instances),
+			geMaxWidth[2], strconv.Itoa(info.Dictionaries),
+			geMaxWidth[3], strconv.Itoa(info.Size),
+			geMaxWidth[4], fmt.Sprintf("%.3f%%", info.SizePercentage))
+	}
+	if len(genericInfos) > 0 {
+		fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	}
+}
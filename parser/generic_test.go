@@ -0,0 +1,28 @@
+package parser
+
+import "testing"
+
+func TestGetGenericInfos(t *testing.T) {
+	nmParsers := []*NmParser{
+		{Symbol: "slices.Sort[go.shape.int]", Size: 100},
+		{Symbol: "slices.Sort[go.shape.string]", Size: 120},
+		{Symbol: "slices.Sort[go.shape.int].stkobj<1>", Size: 8},
+		{Symbol: "slices.Sort[go.shape.int].arginfo1<1>", Size: 4},
+		{Symbol: "slices..dict.Sort[int]", Size: 16},
+		{Symbol: "strings.Join[int]", Size: 20},
+		{Symbol: "main.main", Size: 50},
+	}
+	infos := GetGenericInfos(nmParsers, 318)
+	if len(infos) != 2 {
+		t.Fatalf("GetGenericInfos() got %d groups, want 2", len(infos))
+	}
+
+	sortInfo := infos[0]
+	if sortInfo.Name != "slices.Sort[...]" || sortInfo.Instances != 2 || sortInfo.Dictionaries != 1 || sortInfo.Size != 248 {
+		t.Errorf("got %+v, want slices.Sort[...] with 2 shapes, 1 dictionary and size 248", sortInfo)
+	}
+	joinInfo := infos[1]
+	if joinInfo.Name != "strings.Join[...]" || joinInfo.Instances != 0 || joinInfo.Size != 20 {
+		t.Errorf("got %+v, want strings.Join[...] with 0 shapes and size 20", joinInfo)
+	}
+}
@@ -0,0 +1,182 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const historyFile = "binary.history.json"
+
+// Snapshot the package sizes and total size of a binary file at a point in time
+type Snapshot struct {
+	Module    string         `json:"module"`
+	Label     string         `json:"label"`
+	Time      time.Time      `json:"time"`
+	File      string         `json:"file"`
+	GoVersion string         `json:"goVersion"`
+	FileSize  int            `json:"fileSize"`
+	TotalSize int            `json:"totalSize"`
+	Packages  map[string]int `json:"packages"`
+}
+
+// Snapshot take a snapshot of the package sizes, the module is the main module path of the binary file.
+func (bp *BinaryParser) Snapshot(binaryFile string, label string) *Snapshot {
+	s := &Snapshot{
+		Module:    filepath.Base(binaryFile),
+		Label:     label,
+		Time:      time.Now(),
+		File:      binaryFile,
+		FileSize:  bp.FileSize,
+		TotalSize: bp.TotalSize,
+		Packages:  make(map[string]int, len(bp.PkgInfos)),
+	}
+	if bp.BuildInfo != nil {
+		s.GoVersion = bp.BuildInfo.GoVersion
+		if bp.BuildInfo.Main != nil && bp.BuildInfo.Main.Path != "" {
+			s.Module = bp.BuildInfo.Main.Path
+		} else if bp.BuildInfo.Path != "" {
+			s.Module = bp.BuildInfo.Path
+		}
+	}
+	for _, info := range bp.PkgInfos {
+		s.Packages[strings.TrimRight(info.PkgName, "/")] += info.Size
+	}
+	return s
+}
+
+// LoadSnapshots read all snapshots in the directory, grouped by module and sorted by time.
+func LoadSnapshots(dir string) (map[string][]*Snapshot, error) {
+	snapshots := make(map[string][]*Snapshot)
+	data, err := os.ReadFile(filepath.Join(dir, historyFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snapshots, nil
+		}
+		return nil, err
+	}
+	err = json.Unmarshal(data, &snapshots)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal %s failed: %v", historyFile, err)
+	}
+	for _, ss := range snapshots {
+		sort.SliceStable(ss, func(i, j int) bool { return ss[i].Time.Before(ss[j].Time) })
+	}
+	return snapshots, nil
+}
+
+// SaveSnapshot append the snapshot to the history in the directory.
+func SaveSnapshot(dir string, s *Snapshot) error {
+	snapshots, err := LoadSnapshots(dir)
+	if err != nil {
+		return err
+	}
+	snapshots[s.Module] = append(snapshots[s.Module], s)
+
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return fmt.Errorf("marshal %s failed: %v", historyFile, err)
+	}
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(filepath.Join(dir, historyFile), data, 0644)
+	if err != nil {
+		return fmt.Errorf("write %s failed: %v", historyFile, err)
+	}
+	return nil
+}
+
+// PrintHistory print the trend of the top N packages over the snapshots to the writer, packages
+// are sorted by the size in the latest snapshot.
+func PrintHistory(w io.Writer, snapshots []*Snapshot, topN int, maxWidth int) {
+	if len(snapshots) == 0 {
+		return
+	}
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+
+	var names []string
+	seen := make(map[string]struct{})
+	for _, s := range snapshots {
+		for name := range s.Packages {
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		si, sj := last.Packages[names[i]], last.Packages[names[j]]
+		if si != sj {
+			return si > sj
+		}
+		return names[i] < names[j]
+	})
+
+	nameWidth := maxWidth + 4
+	colWidth := 20 // the width of the time
+	for _, s := range snapshots {
+		if len(s.Label)+4 > colWidth {
+			colWidth = len(s.Label) + 4
+		}
+	}
+
+	n := topN
+	if topN > len(names) {
+		n = len(names)
+	}
+	totalDelta := last.TotalSize - first.TotalSize
+	fmt.Fprintf(w, "\nbinary size history of \"%s\":\nsnapshots: %s, total size: %s => %s bytes, delta: %s bytes, total rows: %s, show top %s rows:\n",
+		last.Module,
+		color.HiCyanString(strconv.Itoa(len(snapshots))),
+		color.HiGreenString(strconv.Itoa(first.TotalSize)),
+		color.HiGreenString(strconv.Itoa(last.TotalSize)),
+		colorDelta(totalDelta, fmt.Sprintf("%+d", totalDelta)),
+		color.HiCyanString(strconv.Itoa(len(names))),
+		color.HiMagentaString(strconv.Itoa(n)))
+
+	title := fmt.Sprintf("%-*s", nameWidth, "Package")
+	dates := fmt.Sprintf("%-*s", nameWidth, "")
+	total := fmt.Sprintf("%-*s", nameWidth, "total size")
+	for _, s := range snapshots {
+		title += fmt.Sprintf("%-*s", colWidth, s.Label)
+		dates += fmt.Sprintf("%-*s", colWidth, s.Time.Format("2006-01-02 15:04"))
+		total += fmt.Sprintf("%-*s", colWidth, strconv.Itoa(s.TotalSize))
+	}
+	title += "Delta"
+	total += colorDelta(totalDelta, fmt.Sprintf("%+d", totalDelta))
+	separators := strings.Repeat("-", len(title))
+	fmt.Fprintln(w, color.HiBlackString(separators))
+	fmt.Fprintln(w, color.HiCyanString(title))
+	fmt.Fprintln(w, color.HiBlackString(dates))
+	fmt.Fprintln(w, color.HiBlackString(separators))
+	for _, name := range names[:n] {
+		line := name
+		if len(line) >= nameWidth {
+			size := nameWidth - 29
+			line = line[:20] + " ... " + line[len(line)-size:]
+		}
+		line = fmt.Sprintf("%-*s", nameWidth, line)
+		for _, s := range snapshots {
+			if size, ok := s.Packages[name]; ok {
+				line += fmt.Sprintf("%-*s", colWidth, strconv.Itoa(size))
+			} else {
+				line += fmt.Sprintf("%-*s", colWidth, "-")
+			}
+		}
+		delta := last.Packages[name] - first.Packages[name]
+		fmt.Fprintln(w, line+colorDelta(delta, fmt.Sprintf("%+d", delta)))
+	}
+	fmt.Fprintln(w, color.HiBlackString(separators))
+	fmt.Fprintln(w, total)
+	fmt.Fprintln(w, color.HiBlackString(separators))
+}
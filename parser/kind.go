@@ -0,0 +1,211 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// symbol kinds
+const (
+	KindFunction = "function"
+	KindMethod   = "method"
+	KindClosure  = "closure"
+	KindWrapper  = "method value wrapper" // -fm
+	KindType     = "type metadata"
+	KindItab     = "itab"
+	KindString   = "string data"
+	KindRuntime  = "runtime metadata" // other symbols generated by the linker, e.g. go:func.*
+	KindData     = "data"
+	KindOther    = "other" // e.g. symbols of C code
+)
+
+// KindInfo the size of the symbols of a kind
+type KindInfo struct {
+	Kind           string  `json:"kind"`
+	Lines          int     `json:"lines"`
+	Size           int     `json:"size"`
+	SizePercentage float32 `json:"sizePercentage"`
+}
+
+// ClassifySymbol parse the kind, receiver type and parent function of the symbol.
+func ClassifySymbol(nm *NmParser) {
+	nm.Kind, nm.Receiver, nm.Parent = classifySymbol(nm.Symbol, nm.Type)
+}
+
+func classifySymbol(symbol string, nmType string) (kind string, receiver string, parent string) {
+	switch {
+	case strings.HasPrefix(symbol, "type:"):
+		return KindType, "", ""
+	case strings.HasPrefix(symbol, "go:itab."):
+		return KindItab, "", ""
+	case strings.HasPrefix(symbol, "go:string."):
+		return KindString, "", ""
+	case strings.HasPrefix(symbol, "go:"), strings.HasPrefix(symbol, "runtime.gcbits."):
+		return KindRuntime, "", ""
+	}
+
+	prefix, rest := splitSymbolPkg(symbol)
+	if prefix == "" {
+		return KindOther, "", ""
+	}
+	if !strings.EqualFold(nmType, "T") {
+		return KindData, "", ""
+	}
+
+	elems := trimABISuffix(splitSymbolElems(rest))
+	if len(elems) > 0 && strings.HasSuffix(elems[len(elems)-1], "-fm") {
+		elems[len(elems)-1] = strings.TrimSuffix(elems[len(elems)-1], "-fm")
+		receiver = parseReceiver(elems)
+		return KindWrapper, receiver, prefix + strings.Join(elems, ".")
+	}
+
+	// the closures are named as parent.func1, parent.func1.1, parent.gowrap1, parent.deferwrap1
+	for i, elem := range elems {
+		if i > 0 && isClosureElem(elem) {
+			receiver = parseReceiver(elems[:i])
+			return KindClosure, receiver, prefix + strings.Join(elems[:i], ".")
+		}
+	}
+
+	// the package initializers, e.g. init.0 and map.init.0
+	if elems[0] == "init" || (len(elems) > 1 && elems[0] == "map" && elems[1] == "init") {
+		return KindFunction, "", ""
+	}
+
+	receiver = parseReceiver(elems)
+	if receiver != "" {
+		return KindMethod, receiver, ""
+	}
+	return KindFunction, "", ""
+}
+
+// remove the suffix of the ABI wrapper, e.g. runtime.memmove.abi0
+func trimABISuffix(elems []string) []string {
+	if n := len(elems); n > 1 && (elems[n-1] == "abi0" || elems[n-1] == "abiinternal") {
+		return elems[:n-1]
+	}
+	return elems
+}
+
+// split the symbol into the package prefix (package path and a dot) and the rest,
+// the package path is before the type parameters.
+func splitSymbolPkg(symbol string) (string, string) {
+	name := symbol
+	if i := strings.IndexAny(name, "[,"); i >= 0 {
+		name = name[:i]
+	}
+	lastSlash := strings.LastIndexByte(name, '/')
+	dot := strings.IndexByte(name[lastSlash+1:], '.')
+	if dot <= 0 {
+		return "", symbol
+	}
+	i := lastSlash + 1 + dot + 1
+	if strings.ContainsAny(symbol[:i], " ()*:") {
+		return "", symbol
+	}
+	return symbol[:i], symbol[i:]
+}
+
+// split the name by dots that are outside of brackets and parentheses
+func splitSymbolElems(name string) []string {
+	var elems []string
+	depth, start := 0, 0
+	for i := 0; i < len(name); i++ {
+		switch name[i] {
+		case '[', '(':
+			depth++
+		case ']', ')':
+			depth--
+		case '.':
+			if depth == 0 {
+				elems = append(elems, name[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(elems, name[start:])
+}
+
+func isClosureElem(elem string) bool {
+	for _, prefix := range []string{"func", "gowrap", "deferwrap"} {
+		if strings.HasPrefix(elem, prefix) {
+			_, err := strconv.Atoi(elem[len(prefix):])
+			return err == nil
+		}
+	}
+	return false
+}
+
+// the receiver is the first element of a method name, e.g. (*T).M or T.M
+func parseReceiver(elems []string) string {
+	if len(elems) < 2 {
+		return ""
+	}
+	if strings.HasPrefix(elems[0], "(") && strings.HasSuffix(elems[0], ")") {
+		return elems[0][1 : len(elems[0])-1]
+	}
+	return elems[0]
+}
+
+// GetKindInfos aggregate the size of symbols by kind, sorted by size.
+func GetKindInfos(nmParsers []*NmParser, totalSize int) []*KindInfo {
+	var kindInfos []*KindInfo
+	kindInfoMap := make(map[string]*KindInfo)
+	for _, nm := range nmParsers {
+		info, ok := kindInfoMap[nm.Kind]
+		if !ok {
+			info = &KindInfo{Kind: nm.Kind}
+			kindInfoMap[nm.Kind] = info
+			kindInfos = append(kindInfos, info)
+		}
+		info.Lines++
+		info.Size += nm.Size
+	}
+
+	for _, info := range kindInfos {
+		if totalSize > 0 {
+			info.SizePercentage = float32(info.Size) / float32(totalSize) * 100
+		}
+	}
+	sort.Slice(kindInfos, func(i, j int) bool { return kindInfos[i].Size > kindInfos[j].Size })
+
+	return kindInfos
+}
+
+func (r *TextRenderer) writeKinds(w io.Writer, report *Report) {
+	kiMaxWidth := []int{24, 11, 11, 15}
+	for i := 0; i < len(kiMaxWidth); i++ {
+		kiMaxWidth[i] += 4
+	}
+
+	kindInfos := report.Kinds
+	title := fmt.Sprintf("%-*s%-*s%-*s%-*s",
+		kiMaxWidth[0], "Kind",
+		kiMaxWidth[1], "Count Rows",
+		kiMaxWidth[2], "Size(bytes)",
+		kiMaxWidth[3], "Percentage(size)")
+	resultTip := "parse symbol kind results:"
+	fmt.Fprintf(w, "\n%s\ntotal size: %s bytes,  total rows: %s:\n",
+		resultTip,
+		r.paint(color.FgHiGreen, strconv.Itoa(report.TotalSize)),
+		r.paint(color.FgHiCyan, strconv.Itoa(len(kindInfos))))
+	separators := strings.Repeat("-", len(title)-4)
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	fmt.Fprintln(w, r.paint(color.FgHiCyan, title))
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	for _, info := range kindInfos {
+		fmt.Fprintf(w, "%-*s%-*s%-*s%-*s\n",
+			kiMaxWidth[0], info.Kind,
+			kiMaxWidth[1], strconv.Itoa(info.Lines),
+			kiMaxWidth[2], strconv.Itoa(info.Size),
+			kiMaxWidth[3], fmt.Sprintf("%.2f%%", info.SizePercentage))
+	}
+	if len(kindInfos) > 0 {
+		fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	}
+}
@@ -0,0 +1,45 @@
+package parser
+
+import "testing"
+
+func TestClassifySymbol(t *testing.T) {
+	tests := []struct {
+		symbol   string
+		nmType   string
+		kind     string
+		receiver string
+		parent   string
+	}{
+		{symbol: "main.main", nmType: "T", kind: KindFunction},
+		{symbol: "github.com/foo/bar.(*Server).Run", nmType: "T", kind: KindMethod, receiver: "*Server"},
+		{symbol: "github.com/foo/bar.Server.String", nmType: "T", kind: KindMethod, receiver: "Server"},
+		{symbol: "strings.(*Builder).WriteString.abi0", nmType: "T", kind: KindMethod, receiver: "*Builder"},
+		{symbol: "runtime.memmove.abi0", nmType: "T", kind: KindFunction},
+		{symbol: "crypto/sha1.blockAVX2.abi0", nmType: "T", kind: KindFunction},
+		{symbol: "runtime.morestack.abiinternal", nmType: "T", kind: KindFunction},
+		{symbol: "net/http.init.0", nmType: "T", kind: KindFunction},
+		{symbol: "net/http.map.init.0", nmType: "T", kind: KindFunction},
+		{symbol: "main.main.func1", nmType: "T", kind: KindClosure, parent: "main.main"},
+		{symbol: "main.main.func1.2", nmType: "T", kind: KindClosure, parent: "main.main"},
+		{symbol: "main.init.func1", nmType: "T", kind: KindClosure, parent: "main.init"},
+		{symbol: "main.(*T).Run.func1", nmType: "T", kind: KindClosure, receiver: "*T", parent: "main.(*T).Run"},
+		{symbol: "main.(*T).Run.gowrap1", nmType: "T", kind: KindClosure, receiver: "*T", parent: "main.(*T).Run"},
+		{symbol: "main.run.deferwrap1", nmType: "T", kind: KindClosure, parent: "main.run"},
+		{symbol: "main.(*T).Close-fm", nmType: "T", kind: KindWrapper, receiver: "*T", parent: "main.(*T).Close"},
+		{symbol: "main.bigTable", nmType: "D", kind: KindData},
+		{symbol: "type:*main.T", nmType: "R", kind: KindType},
+		{symbol: "go:itab.*os.File,io.Writer", nmType: "R", kind: KindItab},
+		{symbol: "go:string.*", nmType: "R", kind: KindString},
+		{symbol: "go:func.*", nmType: "R", kind: KindRuntime},
+		{symbol: "x_cgo_init", nmType: "T", kind: KindOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			kind, receiver, parent := classifySymbol(tt.symbol, tt.nmType)
+			if kind != tt.kind || receiver != tt.receiver || parent != tt.parent {
+				t.Errorf("classifySymbol(%q) = (%q, %q, %q), want (%q, %q, %q)",
+					tt.symbol, kind, receiver, parent, tt.kind, tt.receiver, tt.parent)
+			}
+		})
+	}
+}
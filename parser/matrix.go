@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// PlatformPkgSize the size of a package on each platform
+type PlatformPkgSize struct {
+	Name    string   `json:"name"`
+	Sizes   []int    `json:"sizes"`   // in the same order as the platforms, 0 if the package is missing
+	Missing []string `json:"missing"` // platforms that the package does not appear on
+	Present []bool   `json:"present"` // in the same order as the platforms, whether the package appears
+}
+
+// PlatformMatrix the package sizes of the binary files built for different platforms
+type PlatformMatrix struct {
+	Platforms  []string           `json:"platforms"`
+	Files      []string           `json:"files"`
+	TotalSizes []int              `json:"totalSizes"`
+	Packages   []*PlatformPkgSize `json:"packages"`
+	MaxWidth   int                `json:"-"`
+}
+
+// NewPlatformMatrix build the matrix of package sizes, packages are matched by package name
+// and sorted by the max size on all platforms.
+func NewPlatformMatrix(platforms []string, files []string, bps []*BinaryParser) *PlatformMatrix {
+	m := &PlatformMatrix{
+		Platforms:  platforms,
+		Files:      files,
+		TotalSizes: make([]int, len(bps)),
+	}
+
+	pkgMap := make(map[string]*PlatformPkgSize)
+	for i, bp := range bps {
+		m.TotalSizes[i] = bp.TotalSize
+		for _, info := range bp.PkgInfos {
+			name := strings.TrimRight(info.PkgName, "/")
+			pkg, ok := pkgMap[name]
+			if !ok {
+				pkg = &PlatformPkgSize{Name: name, Sizes: make([]int, len(bps)), Present: make([]bool, len(bps))}
+				pkgMap[name] = pkg
+				m.Packages = append(m.Packages, pkg)
+			}
+			pkg.Sizes[i] += info.Size
+			pkg.Present[i] = true
+		}
+	}
+
+	for _, pkg := range m.Packages {
+		for i, ok := range pkg.Present {
+			if !ok {
+				pkg.Missing = append(pkg.Missing, platforms[i])
+			}
+		}
+	}
+	sort.Slice(m.Packages, func(i, j int) bool {
+		si, sj := maxInt(m.Packages[i].Sizes), maxInt(m.Packages[j].Sizes)
+		if si != sj {
+			return si > sj
+		}
+		return m.Packages[i].Name < m.Packages[j].Name
+	})
+
+	return m
+}
+
+// PartialPackages the packages that only appear on some platforms
+func (m *PlatformMatrix) PartialPackages() []*PlatformPkgSize {
+	var pkgs []*PlatformPkgSize
+	for _, pkg := range m.Packages {
+		if len(pkg.Missing) > 0 {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	return pkgs
+}
+
+// PrintJSON print the matrix in json format to the writer.
+func (m *PlatformMatrix) PrintJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Print print the package sizes of the top N packages on each platform to the writer, the packages
+// that only appear on some platforms are highlighted.
+func (m *PlatformMatrix) Print(w io.Writer, topN int, isOnlyPartial bool) {
+	pkgs := m.Packages
+	if isOnlyPartial {
+		pkgs = m.PartialPackages()
+	}
+
+	colWidth := 16
+	for _, platform := range m.Platforms {
+		if len(platform)+4 > colWidth {
+			colWidth = len(platform) + 4
+		}
+	}
+	nameWidth := m.MaxWidth + 4
+
+	n := topN
+	if topN > len(pkgs) {
+		n = len(pkgs)
+	}
+	fmt.Fprintf(w, "\nparse platform matrix results:\nplatforms: %s, packages: %s, only on some platforms: %s, show top %s rows:\n",
+		color.HiCyanString(strconv.Itoa(len(m.Platforms))),
+		color.HiCyanString(strconv.Itoa(len(m.Packages))),
+		color.HiYellowString(strconv.Itoa(len(m.PartialPackages()))),
+		color.HiMagentaString(strconv.Itoa(n)))
+
+	title := fmt.Sprintf("%-*s", nameWidth, "Package")
+	total := fmt.Sprintf("%-*s", nameWidth, "total size")
+	for i, platform := range m.Platforms {
+		title += fmt.Sprintf("%-*s", colWidth, platform)
+		total += fmt.Sprintf("%-*s", colWidth, strconv.Itoa(m.TotalSizes[i]))
+	}
+	separators := strings.Repeat("-", len(title)-4)
+	fmt.Fprintln(w, color.HiBlackString(separators))
+	fmt.Fprintln(w, color.HiCyanString(title))
+	fmt.Fprintln(w, color.HiBlackString(separators))
+	if len(pkgs) > topN {
+		pkgs = pkgs[:topN]
+	}
+	for _, pkg := range pkgs {
+		name := pkg.Name
+		if len(name) >= nameWidth {
+			size := nameWidth - 29
+			name = name[:20] + " ... " + name[len(name)-size:]
+		}
+		line := fmt.Sprintf("%-*s", nameWidth, name)
+		for i := range m.Platforms {
+			if !pkg.Present[i] {
+				line += fmt.Sprintf("%-*s", colWidth, "-")
+			} else {
+				line += fmt.Sprintf("%-*s", colWidth, strconv.Itoa(pkg.Sizes[i]))
+			}
+		}
+		if len(pkg.Missing) > 0 {
+			line = color.HiYellowString(line)
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintln(w, color.HiBlackString(separators))
+	fmt.Fprintln(w, color.HiGreenString(total))
+	fmt.Fprintln(w, color.HiBlackString(separators))
+}
+
+func maxInt(values []int) int {
+	m := 0
+	for _, v := range values {
+		if v > m {
+			m = v
+		}
+	}
+	return m
+}
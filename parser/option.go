@@ -0,0 +1,41 @@
+package parser
+
+// Option set the options of NewBinaryParser and GoListDeps
+type Option func(*options)
+
+type options struct {
+	grep   string
+	filter *Filter
+	runner CommandRunner
+}
+
+func defaultOptions() *options {
+	return &options{runner: DefaultCommandRunner}
+}
+
+func (o *options) apply(opts ...Option) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// WithGrep only keep the symbols whose name contains grep.
+func WithGrep(grep string) Option {
+	return func(o *options) {
+		o.grep = grep
+	}
+}
+
+// WithCommandRunner set the runner of the external commands, e.g. "go tool nm" and "go list".
+func WithCommandRunner(runner CommandRunner) Option {
+	return func(o *options) {
+		o.runner = runner
+	}
+}
+
+// WithFilter filter the symbols and packages.
+func WithFilter(filter *Filter) Option {
+	return func(o *options) {
+		o.filter = filter
+	}
+}
@@ -2,7 +2,10 @@ package parser
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 
@@ -12,51 +15,81 @@ import (
 type BinaryParser struct {
 	NmParsers []*NmParser
 	PkgInfos  []*PkgInfo
-	TotalSize int
+	BuildInfo *BuildInfo
+	BuildMode string // e.g. exe, c-shared, c-archive, plugin
+	Sections  []*SectionInfo
+	TotalSize int // sum of symbol sizes
+	FileSize  int // size of the binary file on disk
 	MaxWidth  int
+
+	// the binary file is stripped, symbols are recovered from .gopclntab and only contain functions
+	IsTextOnly bool
 }
 
-func NewBinaryParser(file string, grep string) (*BinaryParser, error) {
-	nmParsers, totalSize, err := GetNmParsers(file, grep)
+// NewBinaryParser parse the symbols, packages and sections of the binary file.
+func NewBinaryParser(ctx context.Context, file string, opts ...Option) (*BinaryParser, error) {
+	o := defaultOptions()
+	o.apply(opts...)
+	grep, filter := o.grep, o.filter
+
+	allNmParsers, err := readNmParsers(ctx, o.runner, file)
+	isTextOnly := false
+	if errors.Is(err, ErrNoSymbols) {
+		// the binary file is stripped, fall back to the functions in .gopclntab
+		allNmParsers, err = readPclntabNmParsers(file)
+		isTextOnly = true
+	}
 	if err != nil {
 		return nil, err
 	}
-
-	pkgInfos, subPkgNameMap, err := GetPkgInfos(file, grep)
-	if err != nil {
+	if err = ctx.Err(); err != nil {
 		return nil, err
 	}
+	nmParsers, totalSize := filterNmParsers(allNmParsers, grep)
+	nmParsers = filter.filterNmParsers(nmParsers)
 
-	binaryParser := &BinaryParser{TotalSize: totalSize, NmParsers: nmParsers}
-	for i, info := range pkgInfos {
-		for _, parser := range nmParsers {
-			if info.IsMod {
-				if strings.HasPrefix(parser.Symbol, info.PkgName) || strings.Contains(parser.Symbol, "type:.eq."+info.PkgName) {
-					pkgInfos[i].Size += parser.Size
-					pkgInfos[i].Lines += 1
-				}
-			} else {
-				if strings.Contains(parser.Symbol, info.PkgName) {
-					pkgInfos[i].Size += parser.Size
-					pkgInfos[i].Lines += 1
-				}
-			}
+	buildInfo, err := GetBuildInfo(file)
+	if err != nil {
+		if !IsObjectFile(file) {
+			return nil, err
 		}
-		pkgInfos[i].SizePercentage = float32(info.Size) / float32(totalSize) * 100
+		// archives and object files have no build info, except the go.o member of a c-archive
+		buildInfo = nil
+	}
+	// the modules that do not match grep still own their symbols, only their rows are dropped
+	pkgInfos := AttributeSymbols(nmParsers, buildInfo.PkgInfos(""), totalSize, grep)
+	pkgInfos = grepPkgInfos(pkgInfos, grep)
+	pkgInfos = filter.filterPkgInfos(pkgInfos)
+
+	binaryParser := &BinaryParser{
+		TotalSize:  totalSize,
+		NmParsers:  nmParsers,
+		PkgInfos:   pkgInfos,
+		BuildInfo:  buildInfo,
+		BuildMode:  GetBuildMode(buildInfo),
+		IsTextOnly: isTextOnly,
+	}
+
+	sections, fileSize, err := GetSections(file)
+	if err != nil && !errors.Is(err, errUnknownFormat) {
+		return nil, err
 	}
-	deleteDuplicateSize(pkgInfos, subPkgNameMap)
-	binaryParser.PkgInfos = pkgInfos
+	// the coverage of the sections does not depend on the filters
+	fillSectionSymbolSize(sections, allNmParsers)
+	binaryParser.Sections = sections
+	binaryParser.FileSize = fileSize
 
 	return binaryParser, nil
 }
 
-func (bp *BinaryParser) PrintNmParser(binaryFile string, topN int) {
-	nmMaxWidth := []int{bp.MaxWidth, 8, 4, 11, 15}
+func (r *TextRenderer) writeSymbols(w io.Writer, report *Report) {
+	nmMaxWidth := []int{r.MaxWidth, 8, 4, 11, 15}
 	for i := 0; i < len(nmMaxWidth); i++ {
 		nmMaxWidth[i] += 4
 	}
 
-	totalLine := len(bp.NmParsers)
+	topN := r.limit()
+	totalLine := len(report.Symbols)
 	n := topN
 	if topN > totalLine {
 		n = totalLine
@@ -68,19 +101,22 @@ func (bp *BinaryParser) PrintNmParser(binaryFile string, topN int) {
 		nmMaxWidth[2], "Type",
 		nmMaxWidth[3], "Size(bytes)",
 		nmMaxWidth[4], "Percentage(size)")
-	resultTip := fmt.Sprintf("parse binary file \"%s\" resuls:", binaryFile)
-	fmt.Printf("\n%s\ntotal size: %s bytes,  total rows: %s,  show top %s rows:\n",
+	resultTip := fmt.Sprintf("parse binary file \"%s\" resuls:", report.File)
+	if report.IsTextOnly {
+		resultTip += r.paint(color.FgHiYellow, " (stripped binary, only functions recovered from .gopclntab)")
+	}
+	fmt.Fprintf(w, "\n%s\ntotal size: %s bytes,  total rows: %s,  show top %s rows:\n",
 		resultTip,
-		color.HiGreenString(strconv.Itoa(bp.TotalSize)),
-		color.HiCyanString(strconv.Itoa(totalLine)),
-		color.HiMagentaString(strconv.Itoa(n)))
+		r.paint(color.FgHiGreen, strconv.Itoa(report.TotalSize)),
+		r.paint(color.FgHiCyan, strconv.Itoa(totalLine)),
+		r.paint(color.FgHiMagenta, strconv.Itoa(n)))
 	separators := strings.Repeat("-", len(title)-4)
-	fmt.Println(color.HiBlackString(separators))
-	fmt.Println(color.HiCyanString(title))
-	fmt.Println(color.HiBlackString(separators))
-	nmParsers := bp.NmParsers
-	if len(bp.NmParsers) > topN {
-		nmParsers = bp.NmParsers[:topN]
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	fmt.Fprintln(w, r.paint(color.FgHiCyan, title))
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	nmParsers := report.Symbols
+	if len(report.Symbols) > topN {
+		nmParsers = report.Symbols[:topN]
 	}
 	for _, nm := range nmParsers {
 		symbol := nm.Symbol
@@ -88,7 +124,7 @@ func (bp *BinaryParser) PrintNmParser(binaryFile string, topN int) {
 			size := nmMaxWidth[0] - 29
 			symbol = symbol[:20] + " ... " + symbol[len(symbol)-size:]
 		}
-		fmt.Printf("%-*s%-*s%-*s%-*s%-*s\n",
+		fmt.Fprintf(w, "%-*s%-*s%-*s%-*s%-*s\n",
 			nmMaxWidth[0], symbol,
 			nmMaxWidth[1], nm.Address,
 			nmMaxWidth[2], nm.Type,
@@ -96,33 +132,24 @@ func (bp *BinaryParser) PrintNmParser(binaryFile string, topN int) {
 			nmMaxWidth[4], fmt.Sprintf("%.3f%%", nm.SizePercentage))
 	}
 	if len(nmParsers) > 0 {
-		fmt.Println(color.HiBlackString(separators))
+		fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
 	}
 }
 
-func (bp *BinaryParser) PrintPkgInfo(_ string, topN int) {
-	piMaxWidth := []int{bp.MaxWidth, 11, 11, 15}
+func (r *TextRenderer) writePackages(w io.Writer, report *Report) {
+	piMaxWidth := []int{r.MaxWidth, 11, 11, 15}
 	for i := 0; i < len(piMaxWidth); i++ {
 		piMaxWidth[i] += 4
 	}
 
-	totalLine := len(bp.PkgInfos)
+	topN := r.limit()
+	totalLine := len(report.Packages)
 	n := topN
 	if topN > totalLine {
 		n = totalLine
 	}
-	pkgInfos := bp.PkgInfos
-	depSize := 0
-	modSize := 0
-	sumSize := 0
-	for _, info := range pkgInfos {
-		if info.IsMod {
-			modSize += info.Size
-		} else {
-			depSize += info.Size
-		}
-	}
-	sumSize = depSize + modSize
+	pkgInfos := report.Packages
+	summary := report.Summary
 
 	title := fmt.Sprintf("%-*s%-*s%-*s%-*s",
 		piMaxWidth[0], "Package",
@@ -131,40 +158,55 @@ func (bp *BinaryParser) PrintPkgInfo(_ string, topN int) {
 		piMaxWidth[3], "Percentage(size)")
 
 	resultTip := "parse go mod package results:"
-	fmt.Printf("\n%s\nsum size: %s bytes, dep size: %s bytes, mod size: %s bytes, percentage(sum/total): %s,\ntotal rows: %s, show top %s rows:\n",
+	if report.BuildInfo != nil {
+		resultTip = fmt.Sprintf("parse go mod package results (%s, %s):", report.BuildInfo.Path, report.BuildInfo.GoVersion)
+	}
+	fmt.Fprintf(w, "\n%s\nsum size: %s bytes, dep size: %s bytes, mod size: %s bytes, std size: %s bytes, other size: %s bytes, percentage(sum/total): %s,\ntotal rows: %s, show top %s rows:\n",
 		resultTip,
-		color.HiGreenString(strconv.Itoa(sumSize)),
-		color.HiGreenString(strconv.Itoa(depSize)),
-		color.HiGreenString(strconv.Itoa(modSize)),
-		color.HiGreenString(fmt.Sprintf("%.2f%%", float32(sumSize)/float32(bp.TotalSize)*100)),
-		color.HiCyanString(strconv.Itoa(totalLine)),
-		color.HiMagentaString(strconv.Itoa(n)),
+		r.paint(color.FgHiGreen, strconv.Itoa(summary.SumSize)),
+		r.paint(color.FgHiGreen, strconv.Itoa(summary.DepSize)),
+		r.paint(color.FgHiGreen, strconv.Itoa(summary.ModSize)),
+		r.paint(color.FgHiGreen, strconv.Itoa(summary.StdSize)),
+		r.paint(color.FgHiGreen, strconv.Itoa(summary.OtherSize)),
+		r.paint(color.FgHiGreen, fmt.Sprintf("%.2f%%", summary.Percentage)),
+		r.paint(color.FgHiCyan, strconv.Itoa(totalLine)),
+		r.paint(color.FgHiMagenta, strconv.Itoa(n)),
 	)
 	separators := strings.Repeat("-", len(title)-4)
-	fmt.Println(color.HiBlackString(separators))
-	fmt.Println(color.HiCyanString(title))
-	fmt.Println(color.HiBlackString(separators))
-	if len(bp.PkgInfos) > topN {
-		pkgInfos = bp.PkgInfos[:topN]
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	fmt.Fprintln(w, r.paint(color.FgHiCyan, title))
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	if len(report.Packages) > topN {
+		pkgInfos = report.Packages[:topN]
 	}
 	for _, info := range pkgInfos {
+		percentage := float32(0)
+		if summary.SumSize > 0 {
+			percentage = float32(info.Size) / float32(summary.SumSize) * 100
+		}
 		pkgName := info.PkgName
 		if info.IsMod {
 			pkgName = strings.TrimRight(pkgName, "/") + " (mod)"
+		} else if info.IsStd {
+			pkgName += " (std)"
+		} else if info.IsOther {
+			pkgName += " (unattributed)"
+		} else if info.ReplacePath != "" {
+			pkgName += " (replaced)"
 		}
 		if len(pkgName) > piMaxWidth[0] {
 			size := piMaxWidth[0] - 29
 			pkgName = pkgName[:20] + " ... " + pkgName[len(pkgName)-size:]
 		}
-		fmt.Printf("%-*s%-*s%-*s%-*s\n",
+		fmt.Fprintf(w, "%-*s%-*s%-*s%-*s\n",
 			piMaxWidth[0], pkgName,
 			piMaxWidth[1], strconv.Itoa(info.Lines),
 			piMaxWidth[2], strconv.Itoa(info.Size),
-			piMaxWidth[3], fmt.Sprintf("%.2f%%", float32(info.Size)/float32(sumSize)*100),
+			piMaxWidth[3], fmt.Sprintf("%.2f%%", percentage),
 		)
 	}
 	if len(pkgInfos) > 0 {
-		fmt.Println(color.HiBlackString(separators))
+		fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
 	}
 }
 
@@ -176,40 +218,123 @@ type NmParser struct {
 	Type           string  `json:"type"`
 	Size           int     `json:"size"`
 	SizePercentage float32 `json:"sizePercentage"`
+	PkgPath        string  `json:"pkgPath,omitempty"` // import path parsed from the symbol name
+	Module         string  `json:"module,omitempty"`  // name of the package info that the symbol is attributed to
+	Kind           string  `json:"kind"`
+	Receiver       string  `json:"receiver,omitempty"` // receiver type of a method
+	Parent         string  `json:"parent,omitempty"`   // parent function of a closure or method value wrapper
 }
 
+// GetNmParsers get the symbols of the binary file that contain grep.
 func GetNmParsers(file string, grep string) ([]*NmParser, int, error) {
-	data, err := Exec("go", "tool", "nm", "-size", file)
+	return getNmParsers(context.Background(), DefaultCommandRunner, file, grep)
+}
+
+func getNmParsers(ctx context.Context, runner CommandRunner, file string, grep string) ([]*NmParser, int, error) {
+	allNmParsers, err := readNmParsers(ctx, runner, file)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	nmParsers, totalSize := filterNmParsers(allNmParsers, grep)
+	return nmParsers, totalSize, nil
+}
+
+// read all symbols of the binary file
+func readNmParsers(ctx context.Context, runner CommandRunner, file string) ([]*NmParser, error) {
+	allNmParsers, err := ReadSymbols(file)
+	if errors.Is(err, errUnknownFormat) {
+		allNmParsers, err = getNmParsersByGoTool(ctx, runner, file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dropLocalAliases(allNmParsers), nil
+}
+
+// GetPclntabNmParsers get the function symbols of a stripped binary file from .gopclntab.
+func GetPclntabNmParsers(file string, grep string) ([]*NmParser, int, error) {
+	allNmParsers, err := readPclntabNmParsers(file)
 	if err != nil {
 		return nil, 0, err
 	}
 
+	nmParsers, totalSize := filterNmParsers(allNmParsers, grep)
+	return nmParsers, totalSize, nil
+}
+
+func readPclntabNmParsers(file string) ([]*NmParser, error) {
+	allNmParsers, err := ReadPclntabSymbols(file)
+	if errors.Is(err, errNoPclntab) {
+		return nil, ErrNoSymbols
+	}
+	return allNmParsers, err
+}
+
+func filterNmParsers(allNmParsers []*NmParser, grep string) ([]*NmParser, int) {
 	var nmParsers []*NmParser
 	var totalSize int
+	for _, nm := range allNmParsers {
+		ClassifySymbol(nm)
+		totalSize += nm.Size
+		if grep != "" && !strings.Contains(nm.Symbol, grep) {
+			continue
+		}
+		nmParsers = append(nmParsers, nm)
+	}
+
+	for i := 0; i < len(nmParsers) && totalSize > 0; i++ {
+		nmParsers[i].SizePercentage = float32(nmParsers[i].Size) / float32(totalSize) * 100
+	}
+
+	return nmParsers, totalSize
+}
+
+// keep the packages whose path matches grep, the "other" package holds the symbols that match grep
+func grepPkgInfos(pkgInfos []*PkgInfo, grep string) []*PkgInfo {
+	if grep == "" {
+		return pkgInfos
+	}
+	var result []*PkgInfo
+	for _, info := range pkgInfos {
+		if info.IsOther || strings.Contains(info.PkgName, grep) || strings.Contains(info.ReplacePath, grep) {
+			result = append(result, info)
+		}
+	}
+	return result
+}
+
+// fallback for the file formats that can not be read natively
+func getNmParsersByGoTool(ctx context.Context, runner CommandRunner, file string) ([]*NmParser, error) {
+	data, err := runner.Run(ctx, &Command{Name: "go", Args: []string{"tool", "nm", "-size", file}})
+	if err != nil {
+		return nil, err
+	}
+
+	var nmParsers []*NmParser
 	scanner := bufio.NewScanner(strings.NewReader(string(data)))
 	for scanner.Scan() {
 		line := scanner.Text()
+		// the lines of an archive with multiple members are prefixed with "file(member):"
+		if strings.HasPrefix(line, file+"(") {
+			if i := strings.Index(line[len(file):], "):"); i > 0 {
+				line = line[len(file)+i+2:]
+			}
+		}
 		ss := strings.Fields(line)
-		if len(ss) >= 4 {
+		// the symbols of unknown type in object files are debug information, e.g. go:constinfo.xxx
+		if len(ss) >= 4 && ss[2] != "?" {
 			size, _ := strconv.Atoi(ss[1])
-			totalSize += size
-			if grep != "" && !strings.Contains(line, grep) {
-				continue
-			}
 			nmParsers = append(nmParsers, &NmParser{
 				Address: ss[0],
 				Size:    size,
 				Type:    ss[2],
-				Symbol:  strings.Join(ss[3:], ""),
+				Symbol:  strings.Join(ss[3:], " "),
 			})
 		}
 	}
 
-	for i := 0; i < len(nmParsers); i++ {
-		nmParsers[i].SizePercentage = float32(nmParsers[i].Size) / float32(totalSize) * 100
-	}
-
-	return nmParsers, totalSize, nil
+	return nmParsers, nil
 }
 
 // ------------------------------------------------------------------------------------------
@@ -220,89 +345,86 @@ type PkgInfo struct {
 	Size           int     `json:"size"`
 	SizePercentage float32 `json:"sizePercentage"`
 	Version        string  `json:"version"`
+	Sum            string  `json:"sum,omitempty"`
+	ReplacePath    string  `json:"replacePath,omitempty"` // replaced by this module
+	ReplaceVersion string  `json:"replaceVersion,omitempty"`
 	IsMod          bool    `json:"isMod"`
+	IsStd          bool    `json:"isStd"`   // standard library package
+	IsOther        bool    `json:"isOther"` // the symbols that do not belong to any module or standard library package
 }
 
-func GetPkgInfos(file string, grep string) ([]*PkgInfo, map[string][]string, error) {
-	data, err := Exec("go", "version", "-m", file)
+// OtherPkgName the name of the package info that holds the symbols not attributed to any package
+const OtherPkgName = "other"
+
+func GetPkgInfos(file string, grep string) ([]*PkgInfo, error) {
+	bi, err := GetBuildInfo(file)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	var pkgInfos []*PkgInfo
-	var pkgNames []string
-	subPkgNameMap := make(map[string][]string)
-	scanner := bufio.NewScanner(strings.NewReader(string(data)))
-	for scanner.Scan() {
-		line := scanner.Text()
-		if grep != "" && !strings.Contains(line, grep) {
-			continue
-		}
-		ss := strings.Fields(line)
-		if len(ss) == 4 && ss[0] == "dep" {
-			pkgInfos = append(pkgInfos, &PkgInfo{
-				PkgName: ss[1],
-				Version: ss[2],
-			})
-			findSubPkgNames(ss[1], pkgNames, subPkgNameMap)
-			pkgNames = append(pkgNames, ss[1])
-		} else if len(ss) > 2 && ss[0] == "mod" {
-			pkgInfos = append(pkgInfos, &PkgInfo{
-				PkgName: ss[1] + "/",
-				IsMod:   true,
-			})
-		} else if len(ss) == 3 && ss[0] == "dep" && ss[2] == "(devel)" {
-			pkgInfos = append(pkgInfos, &PkgInfo{
-				PkgName: ss[1] + "/",
-				IsMod:   true,
-			})
+	return bi.PkgInfos(grep), nil
+}
+
+// AttributeSymbols attribute each symbol to exactly one package, the package path is parsed
+// from the symbol name and matched to the module with the longest prefix, the symbols of
+// the standard library are grouped by their import path and appended to the result.
+// The symbols that match neither a module nor a standard library package, e.g. the C symbols
+// x_cgo_munmap.cold, are attributed to the "other" package.
+// If there is no module, e.g. an archive file, all packages are grouped by their import path.
+func AttributeSymbols(nmParsers []*NmParser, pkgInfos []*PkgInfo, totalSize int, grep string) []*PkgInfo {
+	isNoModule := len(pkgInfos) == 0
+	trie := newPkgTrie(pkgInfos)
+	var mainModule *PkgInfo
+	for _, info := range pkgInfos {
+		if info.IsMod {
+			mainModule = info
+			break
 		}
 	}
 
-	return pkgInfos, subPkgNameMap, nil
-}
+	stdPkgInfoMap := make(map[string]*PkgInfo)
+	var otherPkgInfo *PkgInfo
+	for _, nm := range nmParsers {
+		pkgPath := SymbolPkgPath(nm.Symbol)
+		nm.PkgPath = pkgPath
+		if pkgPath == "" {
+			continue
+		}
 
-func findSubPkgNames(currentName string, pkgNames []string, subPkgNameMap map[string][]string) {
-	for _, name := range pkgNames {
-		if len(name) > len(currentName) {
-			if strings.Contains(name, currentName) {
-				if v, ok := subPkgNameMap[currentName]; ok {
-					v = append(v, name)
-					subPkgNameMap[currentName] = v
-				} else {
-					subPkgNameMap[currentName] = []string{name}
+		info := trie.match(pkgPath)
+		switch {
+		case info != nil:
+		case pkgPath == "main" && mainModule != nil:
+			info = mainModule
+		case IsStdPkgPath(pkgPath) || isNoModule:
+			var ok bool
+			info, ok = stdPkgInfoMap[pkgPath]
+			if !ok {
+				if grep != "" && !strings.Contains(pkgPath, grep) {
+					continue
 				}
+				info = &PkgInfo{PkgName: pkgPath, IsStd: IsStdPkgPath(pkgPath)}
+				stdPkgInfoMap[pkgPath] = info
+				pkgInfos = append(pkgInfos, info)
 			}
-		} else if strings.Contains(currentName, name) {
-			if v, ok := subPkgNameMap[name]; ok {
-				v = append(v, currentName)
-				subPkgNameMap[name] = v
-			} else {
-				subPkgNameMap[name] = []string{currentName}
+		default:
+			if otherPkgInfo == nil {
+				otherPkgInfo = &PkgInfo{PkgName: OtherPkgName, IsOther: true}
+				pkgInfos = append(pkgInfos, otherPkgInfo)
 			}
+			info = otherPkgInfo
 		}
+
+		nm.Module = strings.TrimRight(info.PkgName, "/")
+		info.Size += nm.Size
+		info.Lines++
 	}
-}
 
-func deleteDuplicateSize(pkgInfos []*PkgInfo, subPkgNameMap map[string][]string) {
-	for subName, names := range subPkgNameMap {
-		subNameIndex := -1
-		countSize := 0
-		lines := 0
-		for i, info := range pkgInfos {
-			if subName == info.PkgName {
-				subNameIndex = i
-			}
-			for _, name := range names {
-				if name == info.PkgName {
-					countSize += info.Size
-					lines += info.Lines
-				}
-			}
-		}
-		if subNameIndex >= 0 {
-			pkgInfos[subNameIndex].Size -= countSize
-			pkgInfos[subNameIndex].Lines -= lines
+	for _, info := range pkgInfos {
+		if totalSize > 0 {
+			info.SizePercentage = float32(info.Size) / float32(totalSize) * 100
 		}
 	}
+
+	return pkgInfos
 }
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/gosym"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+var errNoPclntab = errors.New("no .gopclntab found")
+
+// ReadPclntabSymbols recover the functions from the .gopclntab of a stripped go binary file,
+// the pclntab is kept even if the binary is built with -ldflags "-s -w", only text symbols
+// can be recovered.
+func ReadPclntabSymbols(file string) ([]*NmParser, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	pclntab, textStart, err := readPclntab(f)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := gosym.NewTable(nil, gosym.NewLineTable(pclntab, textStart))
+	if err != nil {
+		return nil, fmt.Errorf("parse .gopclntab failed: %v", err)
+	}
+
+	nmParsers := make([]*NmParser, 0, len(table.Funcs))
+	for _, fn := range table.Funcs {
+		nmParsers = append(nmParsers, &NmParser{
+			Address: fmt.Sprintf("%x", fn.Entry),
+			Size:    int(fn.End - fn.Entry),
+			Type:    "T",
+			Symbol:  fn.Name,
+		})
+	}
+	if len(nmParsers) == 0 {
+		return nil, ErrNoSymbols
+	}
+
+	return nmParsers, nil
+}
+
+// get the data of pclntab and the start address of text section
+func readPclntab(r io.ReaderAt) ([]byte, uint64, error) {
+	if f, err := elf.NewFile(r); err == nil {
+		text := f.Section(".text")
+		if text == nil {
+			return nil, 0, errNoPclntab
+		}
+		if s := f.Section(".gopclntab"); s != nil {
+			data, err := s.Data()
+			return data, text.Addr, err
+		}
+		for _, s := range f.Sections {
+			if s.Flags&elf.SHF_ALLOC != 0 && s.Type == elf.SHT_PROGBITS && s.Flags&elf.SHF_EXECINSTR == 0 {
+				if data, err := s.Data(); err == nil {
+					if tab := searchPclntab(data); tab != nil {
+						return tab, text.Addr, nil
+					}
+				}
+			}
+		}
+		return nil, 0, errNoPclntab
+	}
+
+	if f, err := macho.NewFile(r); err == nil {
+		return machoPclntab(f)
+	}
+	if f, err := macho.NewFatFile(r); err == nil && len(f.Arches) > 0 {
+		return machoPclntab(f.Arches[0].File)
+	}
+
+	if f, err := pe.NewFile(r); err == nil {
+		text := f.Section(".text")
+		if text == nil {
+			return nil, 0, errNoPclntab
+		}
+		var imageBase uint64
+		switch oh := f.OptionalHeader.(type) {
+		case *pe.OptionalHeader32:
+			imageBase = uint64(oh.ImageBase)
+		case *pe.OptionalHeader64:
+			imageBase = oh.ImageBase
+		}
+		// there is no pclntab section in PE file, search for it in the read-only data
+		for _, name := range []string{".rdata", ".data"} {
+			s := f.Section(name)
+			if s == nil {
+				continue
+			}
+			if data, err := s.Data(); err == nil {
+				if tab := searchPclntab(data); tab != nil {
+					return tab, imageBase + uint64(text.VirtualAddress), nil
+				}
+			}
+		}
+		return nil, 0, errNoPclntab
+	}
+
+	return nil, 0, errUnknownFormat
+}
+
+func machoPclntab(f *macho.File) ([]byte, uint64, error) {
+	text := f.Section("__text")
+	if text == nil {
+		return nil, 0, errNoPclntab
+	}
+	if s := f.Section("__gopclntab"); s != nil {
+		data, err := s.Data()
+		return data, text.Addr, err
+	}
+	return nil, 0, errNoPclntab
+}
+
+// search for the pclntab header of go1.18 and later, the header is
+// magic(4 bytes), padding(2 bytes), instruction size quantum(1 byte), pointer size(1 byte).
+func searchPclntab(data []byte) []byte {
+	for _, magic := range []uint32{0xfffffff1, 0xfffffff0} {
+		for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+			pattern := make([]byte, 6)
+			order.PutUint32(pattern, magic)
+			for offset := 0; offset+8 <= len(data); {
+				i := bytes.Index(data[offset:], pattern)
+				if i < 0 {
+					break
+				}
+				start := offset + i
+				if start+8 > len(data) {
+					break
+				}
+				quantum, ptrSize := data[start+6], data[start+7]
+				if (quantum == 1 || quantum == 2 || quantum == 4) && (ptrSize == 4 || ptrSize == 8) {
+					return data[start:]
+				}
+				offset = start + 1
+			}
+		}
+	}
+	return nil
+}
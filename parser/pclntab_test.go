@@ -0,0 +1,19 @@
+package parser
+
+import "testing"
+
+func TestSearchPclntab(t *testing.T) {
+	header := []byte{0xf1, 0xff, 0xff, 0xff, 0, 0, 1, 8}
+	data := append([]byte{0, 0, 0}, header...)
+	if got := searchPclntab(data); len(got) != len(header) {
+		t.Errorf("searchPclntab() = %v, want the header at offset 3", got)
+	}
+
+	// the magic is in the last 7 bytes, there is no room for the quantum and pointer size
+	for n := 6; n < len(header); n++ {
+		data = append([]byte{0, 0, 0}, header[:n]...)
+		if got := searchPclntab(data); got != nil {
+			t.Errorf("searchPclntab(%v) = %v, want nil", data, got)
+		}
+	}
+}
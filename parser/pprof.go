@@ -0,0 +1,213 @@
+package parser
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// field numbers of profile.proto, https://github.com/google/pprof/blob/main/proto/profile.proto
+const (
+	profileSampleType        = 1
+	profileSample            = 2
+	profileLocation          = 4
+	profileFunction          = 5
+	profileStringTable       = 6
+	profileTimeNanos         = 9
+	profileComment           = 13
+	profileDefaultSampleType = 14
+
+	valueTypeType = 1
+	valueTypeUnit = 2
+
+	sampleLocationID = 1
+	sampleValue      = 2
+
+	locationID   = 1
+	locationLine = 4
+
+	lineFunctionID = 1
+
+	functionID         = 1
+	functionName       = 2
+	functionSystemName = 3
+)
+
+// WritePprof write a gzipped profile.proto, each symbol is a sample whose stack is
+// module => package => symbol and value is the size, it can be viewed by "go tool pprof".
+func (bp *BinaryParser) WritePprof(w io.Writer, binaryFile string) error {
+	return writePprof(w, binaryFile, bp.NmParsers)
+}
+
+func writePprof(w io.Writer, binaryFile string, nmParsers []*NmParser) error {
+	strs := []string{""}
+	strIndex := map[string]int64{"": 0}
+	str := func(s string) int64 {
+		i, ok := strIndex[s]
+		if !ok {
+			i = int64(len(strs))
+			strIndex[s] = i
+			strs = append(strs, s)
+		}
+		return i
+	}
+
+	profile := &protoBuffer{}
+	for _, vt := range [][2]string{{"symbols", "count"}, {"size", "bytes"}} {
+		b := &protoBuffer{}
+		b.int64Field(valueTypeType, str(vt[0]))
+		b.int64Field(valueTypeUnit, str(vt[1]))
+		profile.bytesField(profileSampleType, b.data)
+	}
+
+	// a frame of the stack is a function and a location with the same id
+	frames := make(map[string]uint64)
+	functions, locations := &protoBuffer{}, &protoBuffer{}
+	frame := func(key string, name string) uint64 {
+		id, ok := frames[key]
+		if ok {
+			return id
+		}
+		id = uint64(len(frames) + 1)
+		frames[key] = id
+
+		fn := &protoBuffer{}
+		fn.uint64Field(functionID, id)
+		fn.int64Field(functionName, str(name))
+		fn.int64Field(functionSystemName, str(name))
+		functions.bytesField(profileFunction, fn.data)
+
+		line := &protoBuffer{}
+		line.uint64Field(lineFunctionID, id)
+		loc := &protoBuffer{}
+		loc.uint64Field(locationID, id)
+		loc.bytesField(locationLine, line.data)
+		locations.bytesField(profileLocation, loc.data)
+		return id
+	}
+
+	for _, nm := range nmParsers {
+		if nm.Size <= 0 {
+			continue
+		}
+		modName, pkgPath := symbolGroup(nm)
+		// the leaf frame is the first
+		ids := []uint64{
+			frame("s\x00"+nm.Symbol, nm.Symbol),
+			frame("p\x00"+modName+"\x00"+pkgPath, pkgPath),
+			frame("m\x00"+modName, modName),
+		}
+		sample := &protoBuffer{}
+		sample.packedUint64Field(sampleLocationID, ids)
+		sample.packedInt64Field(sampleValue, []int64{1, int64(nm.Size)})
+		profile.bytesField(profileSample, sample.data)
+	}
+
+	profile.data = append(profile.data, locations.data...)
+	profile.data = append(profile.data, functions.data...)
+	profile.int64Field(profileTimeNanos, time.Now().UnixNano())
+	profile.int64Field(profileComment, str("binary file: "+binaryFile))
+	profile.int64Field(profileDefaultSampleType, str("size"))
+	for _, s := range strs {
+		profile.stringField(profileStringTable, s)
+	}
+
+	gw := gzip.NewWriter(w)
+	_, err := gw.Write(profile.data)
+	if err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// WriteFolded write the symbols in folded stack format "module;package;type;symbol size"
+// for the flame graph tools, e.g. flamegraph.pl and speedscope, the type is the receiver
+// type of a method, or the symbol kind if there is no receiver, e.g. function and type metadata.
+func (bp *BinaryParser) WriteFolded(w io.Writer) error {
+	return writeFolded(w, bp.NmParsers)
+}
+
+func writeFolded(w io.Writer, nmParsers []*NmParser) error {
+	// semicolons separate the frames
+	replacer := strings.NewReplacer(";", ",", "\n", " ")
+	for _, nm := range nmParsers {
+		if nm.Size <= 0 {
+			continue
+		}
+		modName, pkgPath := symbolGroup(nm)
+		_, err := fmt.Fprintf(w, "%s;%s;%s;%s %d\n",
+			replacer.Replace(modName), replacer.Replace(pkgPath), replacer.Replace(foldedType(nm)), replacer.Replace(nm.Symbol), nm.Size)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// the methods of T and *T are grouped under the type T
+func foldedType(nm *NmParser) string {
+	if nm.Receiver != "" {
+		return strings.TrimPrefix(nm.Receiver, "*")
+	}
+	if nm.Kind == "" {
+		return KindOther
+	}
+	return nm.Kind
+}
+
+// protoBuffer a minimal protocol buffers encoder
+type protoBuffer struct {
+	data []byte
+}
+
+func (b *protoBuffer) varint(x uint64) {
+	for x >= 0x80 {
+		b.data = append(b.data, byte(x)|0x80)
+		x >>= 7
+	}
+	b.data = append(b.data, byte(x))
+}
+
+func (b *protoBuffer) key(field int, wireType int) {
+	b.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (b *protoBuffer) uint64Field(field int, x uint64) {
+	if x == 0 {
+		return
+	}
+	b.key(field, 0)
+	b.varint(x)
+}
+
+func (b *protoBuffer) int64Field(field int, x int64) {
+	b.uint64Field(field, uint64(x))
+}
+
+func (b *protoBuffer) bytesField(field int, data []byte) {
+	b.key(field, 2)
+	b.varint(uint64(len(data)))
+	b.data = append(b.data, data...)
+}
+
+func (b *protoBuffer) stringField(field int, s string) {
+	b.bytesField(field, []byte(s))
+}
+
+func (b *protoBuffer) packedUint64Field(field int, xs []uint64) {
+	packed := &protoBuffer{}
+	for _, x := range xs {
+		packed.varint(x)
+	}
+	b.bytesField(field, packed.data)
+}
+
+func (b *protoBuffer) packedInt64Field(field int, xs []int64) {
+	packed := &protoBuffer{}
+	for _, x := range xs {
+		packed.varint(uint64(x))
+	}
+	b.bytesField(field, packed.data)
+}
@@ -0,0 +1,30 @@
+package parser
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteFolded(t *testing.T) {
+	nmParsers := []*NmParser{
+		{Symbol: "github.com/foo/bar.(*Server).Run", Type: "T", Size: 120, Module: "github.com/foo/bar", PkgPath: "github.com/foo/bar"},
+		{Symbol: "github.com/foo/bar.Server.String", Type: "T", Size: 40, Module: "github.com/foo/bar", PkgPath: "github.com/foo/bar"},
+		{Symbol: "strings.ToUpper", Type: "T", Size: 30, Module: "strings", PkgPath: "strings"},
+		{Symbol: "x_cgo_init", Type: "T", Size: 10},
+	}
+	for _, nm := range nmParsers {
+		ClassifySymbol(nm)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := writeFolded(buf, nmParsers); err != nil {
+		t.Fatal(err)
+	}
+	want := "github.com/foo/bar;github.com/foo/bar;Server;github.com/foo/bar.(*Server).Run 120\n" +
+		"github.com/foo/bar;github.com/foo/bar;Server;github.com/foo/bar.Server.String 40\n" +
+		"std;strings;function;strings.ToUpper 30\n" +
+		"other;other;other;x_cgo_init 10\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeFolded() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+
+	"github.com/fatih/color"
+)
+
+// Renderer render the report to the writer
+type Renderer interface {
+	Render(w io.Writer, report *Report) error
+}
+
+// TextRenderer render the report as tables, the report should contain all rows,
+// the top N rows are selected when rendering.
+type TextRenderer struct {
+	TopN      int // show top N rows, all rows are shown if it is not greater than 0
+	MaxWidth  int // max width of the symbol and package names
+	NoColor   bool
+	IsSection bool // show the size of each section
+	IsKind    bool // show the size summary by symbol kind
+	IsGeneric bool // show the instantiations of generic functions
+}
+
+// Render render the symbols and packages, and the optional sections, kinds and generics,
+// the exported symbols are rendered for a shared library or plugin.
+func (r *TextRenderer) Render(w io.Writer, report *Report) error {
+	buf := &bytes.Buffer{}
+	if r.IsSection {
+		r.writeSections(buf, report)
+		buf.WriteString("\n\n")
+	}
+	r.writeSymbols(buf, report)
+	buf.WriteString("\n\n")
+	r.writePackages(buf, report)
+	if IsSharedBuildMode(report.BuildMode) {
+		buf.WriteString("\n\n")
+		r.writeBuildMode(buf, report)
+	}
+	if r.IsKind {
+		buf.WriteString("\n\n")
+		r.writeKinds(buf, report)
+	}
+	if r.IsGeneric {
+		buf.WriteString("\n\n")
+		r.writeGenerics(buf, report)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (r *TextRenderer) limit() int {
+	if r.TopN <= 0 {
+		return math.MaxInt32
+	}
+	return r.TopN
+}
+
+// paint colorize the text like color.HiXxxString, the format is printed as is if there is no argument
+func (r *TextRenderer) paint(attr color.Attribute, format string, a ...interface{}) string {
+	if len(a) > 0 {
+		format = fmt.Sprintf(format, a...)
+	}
+	if r.NoColor {
+		return format
+	}
+	return color.New(attr).Sprint(format)
+}
+
+// JSONRenderer render the report in json format
+type JSONRenderer struct{}
+
+// Render render the report in indented json.
+func (r *JSONRenderer) Render(w io.Writer, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// HTMLRenderer render the report as a self-contained html page with a zoomable treemap
+type HTMLRenderer struct{}
+
+// Render render the treemap of module => package => symbol sizes.
+func (r *HTMLRenderer) Render(w io.Writer, report *Report) error {
+	tmpl, err := template.New("treemap").Parse(treemapHTML)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, map[string]interface{}{
+		"File":      report.File,
+		"TotalSize": report.TotalSize,
+		"Tree":      buildTree(report.File, report.Symbols),
+	})
+}
+
+// PprofRenderer render the report as a gzipped profile.proto
+type PprofRenderer struct{}
+
+// Render render each symbol as a sample whose stack is module => package => symbol.
+func (r *PprofRenderer) Render(w io.Writer, report *Report) error {
+	return writePprof(w, report.File, report.Symbols)
+}
+
+// FoldedRenderer render the report in folded stack format
+type FoldedRenderer struct{}
+
+// Render render each symbol as a "module;package;type;symbol size" line.
+func (r *FoldedRenderer) Render(w io.Writer, report *Report) error {
+	return writeFolded(w, report.Symbols)
+}
@@ -0,0 +1,92 @@
+package parser
+
+// Report the analysis result of the binary file
+type Report struct {
+	File             string           `json:"file"`
+	FileSize         int              `json:"fileSize"`
+	TotalSize        int              `json:"totalSize"`
+	UnattributedSize int              `json:"unattributedSize"` // bytes on disk not covered by any symbol
+	IsTextOnly       bool             `json:"isTextOnly"`       // only functions recovered from .gopclntab
+	BuildInfo        *BuildInfo       `json:"buildInfo"`
+	BuildMode        string           `json:"buildMode"`
+	Summary          *PkgSizeSummary  `json:"summary"`
+	Sections         []*SectionInfo   `json:"sections"`
+	Symbols          []*NmParser      `json:"symbols"`
+	Packages         []*PkgInfo       `json:"packages"`
+	Generics         []*GenericInfo   `json:"generics"`
+	Kinds            []*KindInfo      `json:"kinds"`
+	Exports          []*ExportInfo    `json:"exports,omitempty"`   // only for c-shared, c-archive and plugin
+	Breakdown        []*BreakdownInfo `json:"breakdown,omitempty"` // only for c-shared, c-archive and plugin
+}
+
+// PkgSizeSummary size breakdown of the packages
+type PkgSizeSummary struct {
+	SumSize    int     `json:"sumSize"`
+	DepSize    int     `json:"depSize"`
+	ModSize    int     `json:"modSize"`
+	StdSize    int     `json:"stdSize"`
+	OtherSize  int     `json:"otherSize"`  // not attributed to any package
+	Percentage float32 `json:"percentage"` // sum size / total size
+}
+
+// PkgSizeSummary calculate the size of dependencies, main module, standard library and the unattributed symbols.
+func (bp *BinaryParser) PkgSizeSummary() *PkgSizeSummary {
+	summary := &PkgSizeSummary{}
+	for _, info := range bp.PkgInfos {
+		switch {
+		case info.IsMod:
+			summary.ModSize += info.Size
+		case info.IsStd:
+			summary.StdSize += info.Size
+		case info.IsOther:
+			summary.OtherSize += info.Size
+		default:
+			summary.DepSize += info.Size
+		}
+	}
+	summary.SumSize = summary.DepSize + summary.ModSize + summary.StdSize + summary.OtherSize
+	if bp.TotalSize > 0 {
+		summary.Percentage = float32(summary.SumSize) / float32(bp.TotalSize) * 100
+	}
+	return summary
+}
+
+// Report get the analysis result, only the top N symbols, packages and generics are kept,
+// all of them are kept if topN is not greater than 0.
+func (bp *BinaryParser) Report(binaryFile string, topN int) *Report {
+	nmParsers := bp.NmParsers
+	pkgInfos := bp.PkgInfos
+	genericInfos := GetGenericInfos(bp.NmParsers, bp.TotalSize)
+	if topN > 0 {
+		if len(nmParsers) > topN {
+			nmParsers = nmParsers[:topN]
+		}
+		if len(pkgInfos) > topN {
+			pkgInfos = pkgInfos[:topN]
+		}
+		if len(genericInfos) > topN {
+			genericInfos = genericInfos[:topN]
+		}
+	}
+
+	report := &Report{
+		File:             binaryFile,
+		FileSize:         bp.FileSize,
+		TotalSize:        bp.TotalSize,
+		UnattributedSize: bp.UnattributedSize(),
+		IsTextOnly:       bp.IsTextOnly,
+		BuildInfo:        bp.BuildInfo,
+		BuildMode:        bp.BuildMode,
+		Summary:          bp.PkgSizeSummary(),
+		Sections:         bp.Sections,
+		Symbols:          nmParsers,
+		Packages:         pkgInfos,
+		Generics:         genericInfos,
+		Kinds:            GetKindInfos(bp.NmParsers, bp.TotalSize),
+	}
+	if IsSharedBuildMode(bp.BuildMode) {
+		report.Exports = bp.Exports()
+		report.Breakdown = bp.Breakdown()
+	}
+	return report
+}
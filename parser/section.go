@@ -0,0 +1,230 @@
+package parser
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// SectionInfo a section of the binary file
+type SectionInfo struct {
+	Name       string `json:"name"`
+	Address    string `json:"address"`
+	FileSize   int    `json:"fileSize"`   // bytes on disk
+	MemSize    int    `json:"memSize"`    // bytes in memory after loading
+	SymbolSize int    `json:"symbolSize"` // bytes of the section covered by symbols
+
+	addr           uint64
+	fileSymbolSize int // bytes on disk covered by symbols
+}
+
+// GetSections get the sections of an ELF, Mach-O or PE file and the size of the file.
+func GetSections(file string) ([]*SectionInfo, int, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	fileSize := int(stat.Size())
+
+	if ef, err := elf.NewFile(f); err == nil {
+		return elfSections(ef), fileSize, nil
+	}
+	if mf, err := macho.NewFile(f); err == nil {
+		return machoSections(mf), fileSize, nil
+	}
+	if ff, err := macho.NewFatFile(f); err == nil && len(ff.Arches) > 0 {
+		return machoSections(ff.Arches[0].File), fileSize, nil
+	}
+	if pf, err := pe.NewFile(f); err == nil {
+		return peSections(pf), fileSize, nil
+	}
+
+	return nil, fileSize, errUnknownFormat
+}
+
+func elfSections(f *elf.File) []*SectionInfo {
+	var sections []*SectionInfo
+	for _, s := range f.Sections {
+		if s.Type == elf.SHT_NULL {
+			continue
+		}
+		// the file size of a compressed section is smaller than its size
+		section := &SectionInfo{Name: s.Name, FileSize: int(s.FileSize)}
+		if s.Type == elf.SHT_NOBITS {
+			section.FileSize = 0
+		}
+		if s.Flags&elf.SHF_ALLOC != 0 {
+			section.addr = s.Addr
+			section.MemSize = int(s.Size)
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
+
+func machoSections(f *macho.File) []*SectionInfo {
+	const (
+		sectionType    = 0xff
+		zeroFill       = 0x1
+		gbZeroFill     = 0xc
+		threadZeroFill = 0x12
+	)
+
+	var sections []*SectionInfo
+	for _, s := range f.Sections {
+		section := &SectionInfo{Name: s.Seg + "," + s.Name, FileSize: int(s.Size)}
+		switch s.Flags & sectionType {
+		case zeroFill, gbZeroFill, threadZeroFill:
+			section.FileSize = 0
+		}
+		if s.Seg != "__DWARF" {
+			section.addr = s.Addr
+			section.MemSize = int(s.Size)
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
+
+func peSections(f *pe.File) []*SectionInfo {
+	var imageBase uint64
+	switch oh := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		imageBase = uint64(oh.ImageBase)
+	case *pe.OptionalHeader64:
+		imageBase = oh.ImageBase
+	}
+
+	var sections []*SectionInfo
+	for _, s := range f.Sections {
+		sections = append(sections, &SectionInfo{
+			Name:     s.Name,
+			FileSize: int(s.Size),
+			MemSize:  int(s.VirtualSize),
+			addr:     imageBase + uint64(s.VirtualAddress),
+		})
+	}
+	return sections
+}
+
+// attribute the size of symbols to the sections where they are located, the
+// bytes covered by overlapping symbols are counted only once.
+func fillSectionSymbolSize(sections []*SectionInfo, nmParsers []*NmParser) {
+	var loaded []*SectionInfo
+	for _, s := range sections {
+		s.Address = ""
+		if s.addr > 0 {
+			s.Address = fmt.Sprintf("%x", s.addr)
+			loaded = append(loaded, s)
+		}
+	}
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].addr < loaded[j].addr })
+
+	ranges := make([][][2]uint64, len(loaded))
+	for _, nm := range nmParsers {
+		addr, err := strconv.ParseUint(nm.Address, 16, 64)
+		if err != nil || nm.Size <= 0 {
+			continue
+		}
+		i := sort.Search(len(loaded), func(x int) bool { return loaded[x].addr > addr }) - 1
+		if i >= 0 && addr < loaded[i].addr+uint64(loaded[i].MemSize) {
+			ranges[i] = append(ranges[i], [2]uint64{addr, addr + uint64(nm.Size)})
+		}
+	}
+
+	for i, s := range loaded {
+		s.SymbolSize = coveredSize(ranges[i], s.addr, s.addr+uint64(s.MemSize))
+		// the tail of a section may be zero-initialized data that is not stored in the file
+		s.fileSymbolSize = coveredSize(ranges[i], s.addr, s.addr+uint64(s.FileSize))
+	}
+}
+
+// the number of bytes in [start, end) covered by at least one of the ranges
+func coveredSize(ranges [][2]uint64, start uint64, end uint64) int {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+
+	size := uint64(0)
+	cursor := start
+	for _, r := range ranges {
+		lo, hi := r[0], r[1]
+		if lo < cursor {
+			lo = cursor
+		}
+		if hi > end {
+			hi = end
+		}
+		if lo < hi {
+			size += hi - lo
+			cursor = hi
+		}
+	}
+	return int(size)
+}
+
+// UnattributedSize the bytes of the binary file that are not covered by any symbol.
+func (bp *BinaryParser) UnattributedSize() int {
+	symbolFileSize := 0
+	for _, s := range bp.Sections {
+		symbolFileSize += s.fileSymbolSize
+	}
+	return bp.FileSize - symbolFileSize
+}
+
+func (r *TextRenderer) writeSections(w io.Writer, report *Report) {
+	seMaxWidth := []int{24, 11, 15, 15, 15}
+	for i := 0; i < len(seMaxWidth); i++ {
+		seMaxWidth[i] += 4
+	}
+
+	title := fmt.Sprintf("%-*s%-*s%-*s%-*s%-*s",
+		seMaxWidth[0], "Section",
+		seMaxWidth[1], "Address",
+		seMaxWidth[2], "File(bytes)",
+		seMaxWidth[3], "Memory(bytes)",
+		seMaxWidth[4], "Symbols(bytes)")
+	resultTip := fmt.Sprintf("parse binary file \"%s\" sections:", report.File)
+	unattributedSize := report.UnattributedSize
+	unattributedPercentage := float32(0)
+	if report.FileSize > 0 {
+		unattributedPercentage = float32(unattributedSize) / float32(report.FileSize) * 100
+	}
+	fmt.Fprintf(w, "\n%s\nfile size: %s bytes,  symbol size: %s bytes,  unattributed: %s bytes (%s)\n",
+		resultTip,
+		r.paint(color.FgHiGreen, strconv.Itoa(report.FileSize)),
+		r.paint(color.FgHiGreen, strconv.Itoa(report.TotalSize)),
+		r.paint(color.FgHiRed, strconv.Itoa(unattributedSize)),
+		r.paint(color.FgHiRed, fmt.Sprintf("%.2f%%", unattributedPercentage)))
+	separators := strings.Repeat("-", len(title)-4)
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	fmt.Fprintln(w, r.paint(color.FgHiCyan, title))
+	fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	for _, s := range report.Sections {
+		name := s.Name
+		if len(name) >= seMaxWidth[0] {
+			name = name[:seMaxWidth[0]-5] + "..."
+		}
+		fmt.Fprintf(w, "%-*s%-*s%-*s%-*s%-*s\n",
+			seMaxWidth[0], name,
+			seMaxWidth[1], s.Address,
+			seMaxWidth[2], strconv.Itoa(s.FileSize),
+			seMaxWidth[3], strconv.Itoa(s.MemSize),
+			seMaxWidth[4], strconv.Itoa(s.SymbolSize))
+	}
+	if len(report.Sections) > 0 {
+		fmt.Fprintln(w, r.paint(color.FgHiBlack, separators))
+	}
+}
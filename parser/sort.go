@@ -1,6 +1,10 @@
 package parser
 
-import "strconv"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 type ByNmSize struct {
 	NmParsers []*NmParser
@@ -38,12 +42,10 @@ type ByNmAddress struct {
 func (a ByNmAddress) Len() int      { return len(a.NmParsers) }
 func (a ByNmAddress) Swap(i, j int) { a.NmParsers[i], a.NmParsers[j] = a.NmParsers[j], a.NmParsers[i] }
 func (a ByNmAddress) Less(i, j int) bool {
-	ai, _ := strconv.ParseInt(a.NmParsers[i].Address, 16, 64)
-	aj, _ := strconv.ParseInt(a.NmParsers[j].Address, 16, 64)
 	if a.IsAsc {
-		return ai < aj
+		return compareAddress(a.NmParsers[i].Address, a.NmParsers[j].Address) < 0
 	}
-	return ai > aj
+	return compareAddress(a.NmParsers[i].Address, a.NmParsers[j].Address) > 0
 }
 
 // --------------------------------------------------------------------------------
@@ -85,7 +87,152 @@ func (a ByPkgLines) Len() int      { return len(a.PkgInfos) }
 func (a ByPkgLines) Swap(i, j int) { a.PkgInfos[i], a.PkgInfos[j] = a.PkgInfos[j], a.PkgInfos[i] }
 func (a ByPkgLines) Less(i, j int) bool {
 	if a.IsAsc {
-		return a.PkgInfos[i].Lines > a.PkgInfos[j].Lines
+		return a.PkgInfos[i].Lines < a.PkgInfos[j].Lines
 	}
-	return a.PkgInfos[i].Lines < a.PkgInfos[j].Lines
+	return a.PkgInfos[i].Lines > a.PkgInfos[j].Lines
+}
+
+// --------------------------------------------------------------------------------
+
+// SortKey a sort key and its direction
+type SortKey struct {
+	Name  string
+	IsAsc bool
+}
+
+// comparators of symbols, the aliases are normalized by nmSortKeyAliases
+var nmComparators = map[string]func(a, b *NmParser) int{
+	"size":    func(a, b *NmParser) int { return compareInt(a.Size, b.Size) },
+	"address": func(a, b *NmParser) int { return compareAddress(a.Address, b.Address) },
+	"symbol":  func(a, b *NmParser) int { return strings.Compare(a.Symbol, b.Symbol) },
+	"type":    func(a, b *NmParser) int { return strings.Compare(a.Type, b.Type) },
+	"kind":    func(a, b *NmParser) int { return strings.Compare(a.Kind, b.Kind) },
+	"package": func(a, b *NmParser) int { return strings.Compare(a.PkgPath, b.PkgPath) },
+	"module":  func(a, b *NmParser) int { return strings.Compare(a.Module, b.Module) },
+}
+
+var nmSortKeyAliases = map[string]string{"addr": "address", "sym": "symbol", "name": "symbol", "pkg": "package", "mod": "module"}
+
+// comparators of packages, the aliases are normalized by pkgSortKeyAliases
+var pkgComparators = map[string]func(a, b *PkgInfo) int{
+	"size":    func(a, b *PkgInfo) int { return compareInt(a.Size, b.Size) },
+	"name":    func(a, b *PkgInfo) int { return strings.Compare(a.PkgName, b.PkgName) },
+	"lines":   func(a, b *PkgInfo) int { return compareInt(a.Lines, b.Lines) },
+	"version": func(a, b *PkgInfo) int { return strings.Compare(a.Version, b.Version) },
+}
+
+var pkgSortKeyAliases = map[string]string{"package": "name", "pkg": "name", "rows": "lines", "count": "lines"}
+
+// ParseNmSortKeys parse the sort keys of symbols separated by comma, a key can be suffixed
+// with ":asc" or ":desc", otherwise the direction is isAsc, e.g. "module,size:desc".
+func ParseNmSortKeys(s string, isAsc bool) ([]SortKey, error) {
+	keys, err := parseSortKeys(s, isAsc, nmSortKeyAliases)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if _, ok := nmComparators[key.Name]; !ok {
+			return nil, fmt.Errorf("unknown symbol sort key %q, supported keys: size, address, symbol, type, kind, package, module", key.Name)
+		}
+	}
+	return keys, nil
+}
+
+// ParsePkgSortKeys parse the sort keys of packages, the format is the same as ParseNmSortKeys.
+func ParsePkgSortKeys(s string, isAsc bool) ([]SortKey, error) {
+	keys, err := parseSortKeys(s, isAsc, pkgSortKeyAliases)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if _, ok := pkgComparators[key.Name]; !ok {
+			return nil, fmt.Errorf("unknown package sort key %q, supported keys: size, name, lines, version", key.Name)
+		}
+	}
+	return keys, nil
+}
+
+func parseSortKeys(s string, isAsc bool, aliases map[string]string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, field := range strings.Split(s, ",") {
+		name, direction, _ := strings.Cut(strings.ToLower(strings.TrimSpace(field)), ":")
+		if name == "" {
+			continue
+		}
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+		key := SortKey{Name: name, IsAsc: isAsc}
+		switch direction {
+		case "":
+		case "asc":
+			key.IsAsc = true
+		case "desc":
+			key.IsAsc = false
+		default:
+			return nil, fmt.Errorf("unknown sort direction %q of key %q, use asc or desc", direction, name)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("sort key is empty")
+	}
+	return keys, nil
+}
+
+// ByNmKeys sort symbols by multiple keys, the later keys are used when the former keys are equal
+type ByNmKeys struct {
+	NmParsers []*NmParser
+	Keys      []SortKey
+}
+
+func (a ByNmKeys) Len() int      { return len(a.NmParsers) }
+func (a ByNmKeys) Swap(i, j int) { a.NmParsers[i], a.NmParsers[j] = a.NmParsers[j], a.NmParsers[i] }
+func (a ByNmKeys) Less(i, j int) bool {
+	for _, key := range a.Keys {
+		c := nmComparators[key.Name](a.NmParsers[i], a.NmParsers[j])
+		if c != 0 {
+			return (c < 0) == key.IsAsc
+		}
+	}
+	return false
+}
+
+// ByPkgKeys sort packages by multiple keys, the later keys are used when the former keys are equal
+type ByPkgKeys struct {
+	PkgInfos []*PkgInfo
+	Keys     []SortKey
+}
+
+func (a ByPkgKeys) Len() int      { return len(a.PkgInfos) }
+func (a ByPkgKeys) Swap(i, j int) { a.PkgInfos[i], a.PkgInfos[j] = a.PkgInfos[j], a.PkgInfos[i] }
+func (a ByPkgKeys) Less(i, j int) bool {
+	for _, key := range a.Keys {
+		c := pkgComparators[key.Name](a.PkgInfos[i], a.PkgInfos[j])
+		if c != 0 {
+			return (c < 0) == key.IsAsc
+		}
+	}
+	return false
+}
+
+func compareInt(a, b int) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// the addresses are hexadecimal
+func compareAddress(a, b string) int {
+	ai, _ := strconv.ParseUint(a, 16, 64)
+	bi, _ := strconv.ParseUint(b, 16, 64)
+	if ai < bi {
+		return -1
+	} else if ai > bi {
+		return 1
+	}
+	return 0
 }
@@ -0,0 +1,272 @@
+package parser
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+var (
+	// ErrNoSymbols the binary file does not contain a symbol table
+	ErrNoSymbols = errors.New("no symbols")
+
+	errUnknownFormat = errors.New("unknown binary file format")
+)
+
+type symbol struct {
+	Name string
+	Addr uint64
+	Size uint64
+	Code byte
+}
+
+// ReadSymbols read the symbol table of an ELF, Mach-O or PE file directly,
+// the result is the same as the output of "go tool nm -size".
+func ReadSymbols(file string) ([]*NmParser, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	syms, err := readSymbols(f)
+	if errors.Is(err, errUnknownFormat) {
+		// the go code of a c-archive is in the member go.o
+		member, mErr := archiveMember(f, goObjectMember)
+		switch {
+		case mErr == nil:
+			syms, err = readSymbols(member)
+		case errors.Is(mErr, errMalformedArchive):
+			return nil, fmt.Errorf("%s: %w", file, mErr)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	// a stripped binary may still contain the undefined symbols imported from shared libraries
+	isStripped := true
+	for _, s := range syms {
+		if s.Code != 'U' {
+			isStripped = false
+			break
+		}
+	}
+	if isStripped {
+		return nil, ErrNoSymbols
+	}
+
+	nmParsers := make([]*NmParser, 0, len(syms))
+	for _, s := range syms {
+		nmParsers = append(nmParsers, &NmParser{
+			Address: fmt.Sprintf("%x", s.Addr),
+			Size:    int(s.Size),
+			Type:    string(s.Code),
+			Symbol:  s.Name,
+		})
+	}
+
+	return nmParsers, nil
+}
+
+func readSymbols(r io.ReaderAt) ([]symbol, error) {
+	if f, err := elf.NewFile(r); err == nil {
+		return elfSymbols(f)
+	}
+	if f, err := macho.NewFile(r); err == nil {
+		return machoSymbols(f)
+	}
+	if f, err := macho.NewFatFile(r); err == nil {
+		if len(f.Arches) == 0 {
+			return nil, errUnknownFormat
+		}
+		return machoSymbols(f.Arches[0].File)
+	}
+	if f, err := pe.NewFile(r); err == nil {
+		return peSymbols(f)
+	}
+	return nil, errUnknownFormat
+}
+
+func elfSymbols(f *elf.File) ([]symbol, error) {
+	elfSyms, err := f.Symbols()
+	if err != nil {
+		if errors.Is(err, elf.ErrNoSymbols) {
+			return nil, ErrNoSymbols
+		}
+		return nil, err
+	}
+
+	syms := make([]symbol, 0, len(elfSyms))
+	for _, s := range elfSyms {
+		sym := symbol{Name: s.Name, Addr: s.Value, Size: s.Size, Code: '?'}
+		switch s.Section {
+		case elf.SHN_UNDEF:
+			sym.Code = 'U'
+		case elf.SHN_COMMON:
+			sym.Code = 'B'
+		default:
+			i := int(s.Section)
+			if i < 0 || i >= len(f.Sections) {
+				break
+			}
+			sect := f.Sections[i]
+			switch sect.Flags & (elf.SHF_WRITE | elf.SHF_ALLOC | elf.SHF_EXECINSTR) {
+			case elf.SHF_ALLOC | elf.SHF_EXECINSTR:
+				sym.Code = 'T'
+			case elf.SHF_ALLOC:
+				sym.Code = 'R'
+			case elf.SHF_ALLOC | elf.SHF_WRITE:
+				if sect.Type == elf.SHT_NOBITS {
+					sym.Code = 'B'
+				} else {
+					sym.Code = 'D'
+				}
+			}
+		}
+		if elf.ST_BIND(s.Info) == elf.STB_LOCAL {
+			sym.Code += 'a' - 'A'
+		}
+		syms = append(syms, sym)
+	}
+
+	return syms, nil
+}
+
+func machoSymbols(f *macho.File) ([]symbol, error) {
+	if f.Symtab == nil {
+		return nil, ErrNoSymbols
+	}
+
+	const stabTypeMask = 0xe0 // stab debug info
+
+	var syms []symbol
+	for _, s := range f.Symtab.Syms {
+		if s.Type&stabTypeMask != 0 {
+			continue
+		}
+		sym := symbol{Name: s.Name, Addr: s.Value, Code: '?'}
+		if s.Sect == 0 {
+			sym.Code = 'U'
+		} else if int(s.Sect) <= len(f.Sections) {
+			sect := f.Sections[s.Sect-1]
+			switch sect.Seg {
+			case "__TEXT", "__DATA_CONST":
+				sym.Code = 'R'
+			case "__DATA":
+				sym.Code = 'D'
+			}
+			switch sect.Seg + " " + sect.Name {
+			case "__TEXT __text":
+				sym.Code = 'T'
+			case "__DATA __bss", "__DATA __noptrbss":
+				sym.Code = 'B'
+			}
+		}
+		syms = append(syms, sym)
+	}
+	fillSizeByNextAddress(syms)
+
+	return syms, nil
+}
+
+func peSymbols(f *pe.File) ([]symbol, error) {
+	const (
+		nUndef = 0  // an undefined (extern) symbol
+		nAbs   = -1 // an absolute symbol
+		nDebug = -2 // a debugging symbol
+
+		sectText  = 0x20
+		sectData  = 0x40
+		sectBss   = 0x80
+		sectPermW = 0x80000000
+	)
+
+	// when using internal linking BSS is put into the .data section, anything
+	// in a data section past runtime.bss is a BSS symbol.
+	var bssAddr uint32
+	var bssSectionNumber int16
+	for _, s := range f.Symbols {
+		if s.Name == "runtime.bss" {
+			bssAddr = s.Value
+			bssSectionNumber = s.SectionNumber
+			break
+		}
+	}
+
+	var imageBase uint64
+	switch oh := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		imageBase = uint64(oh.ImageBase)
+	case *pe.OptionalHeader64:
+		imageBase = oh.ImageBase
+	}
+
+	var syms []symbol
+	for _, s := range f.Symbols {
+		sym := symbol{Name: s.Name, Addr: uint64(s.Value), Code: '?'}
+		switch s.SectionNumber {
+		case nUndef:
+			sym.Code = 'U'
+		case nAbs:
+			sym.Code = 'C'
+		case nDebug:
+		default:
+			if s.SectionNumber < 0 || len(f.Sections) < int(s.SectionNumber) {
+				return nil, fmt.Errorf("invalid section number %d in symbol table", s.SectionNumber)
+			}
+			sect := f.Sections[s.SectionNumber-1]
+			ch := sect.Characteristics
+			switch {
+			case ch&sectText != 0:
+				sym.Code = 'T'
+			case ch&sectData != 0:
+				if ch&sectPermW == 0 {
+					sym.Code = 'R'
+				} else if bssSectionNumber == s.SectionNumber && bssAddr > 0 && s.Value >= bssAddr {
+					sym.Code = 'B'
+				} else if s.Value >= sect.Size {
+					sym.Code = 'B'
+				} else {
+					sym.Code = 'D'
+				}
+			case ch&sectBss != 0:
+				sym.Code = 'B'
+			}
+			sym.Addr += imageBase + uint64(sect.VirtualAddress)
+		}
+		syms = append(syms, sym)
+	}
+	if len(syms) == 0 {
+		return nil, ErrNoSymbols
+	}
+	fillSizeByNextAddress(syms)
+
+	return syms, nil
+}
+
+// Mach-O and PE symbol tables have no size, the size of a symbol is inferred
+// by looking at where the next symbol begins, undefined symbols take up no space.
+func fillSizeByNextAddress(syms []symbol) {
+	addrs := make([]uint64, 0, len(syms))
+	for _, s := range syms {
+		if s.Code != 'U' {
+			addrs = append(addrs, s.Addr)
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i] < addrs[j] })
+
+	for i := range syms {
+		if syms[i].Code == 'U' {
+			continue
+		}
+		j := sort.Search(len(addrs), func(x int) bool { return addrs[x] > syms[i].Addr })
+		if j < len(addrs) {
+			syms[i].Size = addrs[j] - syms[i].Addr
+		}
+	}
+}
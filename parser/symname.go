@@ -0,0 +1,65 @@
+package parser
+
+import "strings"
+
+// prefixes of the symbols generated by the compiler for a type or an interface table
+var typeSymbolPrefixes = []string{"type:.eq.", "type:.hash.", "type:", "go:itab."}
+
+// the elements generated by the compiler that look like package paths, e.g. type:noalg.xxx,
+// go.shape.int, type:map.group[string]int, "go" and "map" are keywords and can not be package names
+var pseudoPkgPaths = map[string]bool{"go": true, "map": true, "noalg": true}
+
+// SymbolPkgPath get the import path of the package that a go symbol belongs to,
+// e.g. "github.com/foo/bar.(*T).Method" => "github.com/foo/bar", return empty
+// string if the symbol does not belong to any package.
+func SymbolPkgPath(symbol string) string {
+	for _, prefix := range typeSymbolPrefixes {
+		if strings.HasPrefix(symbol, prefix) {
+			symbol = strings.TrimLeft(symbol[len(prefix):], "*[]0123456789")
+			break
+		}
+	}
+	if strings.HasPrefix(symbol, "go:") {
+		return ""
+	}
+
+	prefix, _ := splitSymbolPkg(symbol)
+	if prefix == "" {
+		return ""
+	}
+	pkgPath := prefix[:len(prefix)-1]
+	// local symbols generated by the compiler, e.g. $f64.3ff0000000000000, gclocals·xxx, type:.eq.[2M8SS]
+	if strings.ContainsAny(pkgPath, "$·[]<>") || strings.HasPrefix(pkgPath, ".") || pseudoPkgPaths[pkgPath] {
+		return ""
+	}
+
+	// the dots in the last element of the package path are escaped as %2e
+	return strings.ReplaceAll(pkgPath, "%2e", ".")
+}
+
+// the first elements of the standard library package paths, "go list std | cut -d/ -f1",
+// the C symbols and the local symbols of the compiler look like package paths but are not
+// in it, e.g. x_cgo_munmap.cold and _.goready.func1.
+var stdTopLevels = map[string]bool{
+	"archive": true, "arena": true, "bufio": true, "bytes": true, "cmd": true, "cmp": true,
+	"compress": true, "container": true, "context": true, "crypto": true, "database": true,
+	"debug": true, "embed": true, "encoding": true, "errors": true, "expvar": true, "flag": true,
+	"fmt": true, "go": true, "hash": true, "html": true, "image": true, "index": true,
+	"internal": true, "io": true, "iter": true, "log": true, "maps": true, "math": true,
+	"mime": true, "net": true, "os": true, "path": true, "plugin": true, "reflect": true,
+	"regexp": true, "runtime": true, "simd": true, "slices": true, "sort": true, "strconv": true,
+	"strings": true, "structs": true, "sync": true, "syscall": true, "testing": true, "text": true,
+	"time": true, "unicode": true, "unique": true, "unsafe": true, "uuid": true, "vendor": true,
+	"weak": true,
+}
+
+// IsStdPkgPath whether the package path belongs to the go standard library,
+// the first element of a standard library path is one of the standard top level directories.
+func IsStdPkgPath(pkgPath string) bool {
+	// "go" is not a package, it comes from the file symbol "go.go"
+	if pkgPath == "" || pkgPath == "main" || pkgPath == "go" {
+		return false
+	}
+	elem, _, _ := strings.Cut(pkgPath, "/")
+	return stdTopLevels[elem]
+}
@@ -0,0 +1,26 @@
+package parser
+
+import "testing"
+
+func TestIsStdPkgPath(t *testing.T) {
+	tests := map[string]bool{
+		"runtime":                 true,
+		"net/http":                true,
+		"internal/runtime/atomic": true,
+		"vendor/golang.org/x/net": true,
+		"go/ast":                  true,
+		"go":                      false,
+		"main":                    false,
+		"":                        false,
+		"github.com/foo/bar":      false,
+		"x_cgo_munmap":            false,
+		"_":                       false,
+		"myapp/internal/server":   false,
+		"golang.org/x/net/http2":  false,
+	}
+	for pkgPath, want := range tests {
+		if got := IsStdPkgPath(pkgPath); got != want {
+			t.Errorf("IsStdPkgPath(%q) = %v, want %v", pkgPath, got, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package parser
+
+import (
+	_ "embed"
+	"sort"
+)
+
+//go:embed treemap.html
+var treemapHTML string
+
+// TreeNode a node of the size tree, module => package => symbol
+type TreeNode struct {
+	Name     string      `json:"name"`
+	Size     int         `json:"size"`
+	Children []*TreeNode `json:"children,omitempty"`
+
+	childMap map[string]*TreeNode
+}
+
+func (n *TreeNode) child(name string) *TreeNode {
+	if n.childMap == nil {
+		n.childMap = make(map[string]*TreeNode)
+	}
+	c, ok := n.childMap[name]
+	if !ok {
+		c = &TreeNode{Name: name}
+		n.childMap[name] = c
+		n.Children = append(n.Children, c)
+	}
+	return c
+}
+
+func (n *TreeNode) sortBySize() {
+	sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Size > n.Children[j].Size })
+	for _, c := range n.Children {
+		c.sortBySize()
+	}
+}
+
+// Tree group the symbols into a module => package => symbol tree, symbols of the
+// standard library are grouped under "std", others that are not part of any module
+// are grouped under "other".
+func (bp *BinaryParser) Tree(name string) *TreeNode {
+	return buildTree(name, bp.NmParsers)
+}
+
+func buildTree(name string, nmParsers []*NmParser) *TreeNode {
+	root := &TreeNode{Name: name}
+	for _, nm := range nmParsers {
+		if nm.Size <= 0 {
+			continue
+		}
+		modName, pkgPath := symbolGroup(nm)
+		modNode := root.child(modName)
+		pkgNode := modNode.child(pkgPath)
+		pkgNode.child(nm.Symbol).Size += nm.Size
+		pkgNode.Size += nm.Size
+		modNode.Size += nm.Size
+		root.Size += nm.Size
+	}
+	root.sortBySize()
+
+	return root
+}
+
+// the module and package that the symbol is grouped under
+func symbolGroup(nm *NmParser) (string, string) {
+	modName, pkgPath := nm.Module, nm.PkgPath
+	if modName == "" {
+		modName = "other"
+	} else if IsStdPkgPath(modName) {
+		modName = "std"
+	}
+	if pkgPath == "" {
+		pkgPath = "other"
+	}
+	return modName, pkgPath
+}
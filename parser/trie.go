@@ -0,0 +1,54 @@
+package parser
+
+import "strings"
+
+// pkgTrie a prefix tree of module paths split by "/", used to find the
+// module that a package belongs to by the longest matching prefix.
+type pkgTrie struct {
+	children map[string]*pkgTrie
+	info     *PkgInfo
+}
+
+func newPkgTrie(pkgInfos []*PkgInfo) *pkgTrie {
+	root := &pkgTrie{}
+	for _, info := range pkgInfos {
+		if info.IsStd {
+			continue
+		}
+		root.insert(strings.TrimRight(info.PkgName, "/"), info)
+	}
+	return root
+}
+
+func (t *pkgTrie) insert(path string, info *PkgInfo) {
+	node := t
+	for _, elem := range strings.Split(path, "/") {
+		if node.children == nil {
+			node.children = make(map[string]*pkgTrie)
+		}
+		child, ok := node.children[elem]
+		if !ok {
+			child = &pkgTrie{}
+			node.children[elem] = child
+		}
+		node = child
+	}
+	node.info = info
+}
+
+// match get the module with the longest path that is a prefix of pkgPath.
+func (t *pkgTrie) match(pkgPath string) *PkgInfo {
+	var info *PkgInfo
+	node := t
+	for _, elem := range strings.Split(pkgPath, "/") {
+		child, ok := node.children[elem]
+		if !ok {
+			break
+		}
+		node = child
+		if node.info != nil {
+			info = node.info
+		}
+	}
+	return info
+}
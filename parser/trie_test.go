@@ -0,0 +1,155 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPkgTrieMatch(t *testing.T) {
+	pkgInfos := []*PkgInfo{
+		{PkgName: "example.com/app/", IsMod: true},
+		{PkgName: "example.com/a"},
+		{PkgName: "example.com/a/b"},
+		{PkgName: "runtime", IsStd: true},
+	}
+	trie := newPkgTrie(pkgInfos)
+
+	tests := map[string]string{
+		"example.com/app":            "example.com/app/",
+		"example.com/app/internal/x": "example.com/app/",
+		"example.com/a":              "example.com/a",
+		"example.com/a/c":            "example.com/a",
+		"example.com/a/b":            "example.com/a/b",
+		"example.com/a/b/c":          "example.com/a/b",
+		"example.com/ab":             "",
+		"example.com":                "",
+		"runtime":                    "", // the standard library is not in the trie
+		"main":                       "",
+	}
+	for pkgPath, want := range tests {
+		var got string
+		if info := trie.match(pkgPath); info != nil {
+			got = info.PkgName
+		}
+		if got != want {
+			t.Errorf("match(%q) = %q, want %q", pkgPath, got, want)
+		}
+	}
+}
+
+func TestAttributeSymbols(t *testing.T) {
+	tests := []struct {
+		name   string
+		symbol string
+		module string // the package info that the symbol is attributed to, empty if none
+	}{
+		{"module", "example.com/a.F", "example.com/a"},
+		{"sub package of module", "example.com/a/c.H", "example.com/a"},
+		{"nested module", "example.com/a/b.G", "example.com/a/b"},
+		{"nested module method", "example.com/a/b.(*T).Close", "example.com/a/b"},
+		{"same prefix other module", "example.com/ab.X", OtherPkgName},
+		{"main package", "main.main", "example.com/app"},
+		{"main module package", "example.com/app/internal/x.Y", "example.com/app"},
+		{"std", "runtime.mallocgc", "runtime"},
+		{"std nested", "net/http.(*Server).Serve", "net/http"},
+		{"std second symbol", "runtime.gcStart", "runtime"},
+		{"c symbol", "x_cgo_munmap.cold", OtherPkgName},
+		{"generic function", "example.com/a/b.Map[go.shape.int,go.shape.string]", "example.com/a/b"},
+		{"generic method", "example.com/a.(*List[go.shape.int]).Push", "example.com/a"},
+		{"type eq", "type:.eq.example.com/a/b.T", "example.com/a/b"},
+		{"type eq array", "type:.eq.[2]example.com/a.T", "example.com/a"},
+		{"type eq local", "type:.eq.[2M8SS]", ""},
+		{"no package", "_rt0_amd64_linux", ""},
+		{"go prefix", "go:buildinfo", ""},
+	}
+
+	var nmParsers []*NmParser
+	for i, tt := range tests {
+		nmParsers = append(nmParsers, &NmParser{Symbol: tt.symbol, Size: 1 << i})
+	}
+	pkgInfos := []*PkgInfo{
+		{PkgName: "example.com/app/", IsMod: true},
+		{PkgName: "example.com/a"},
+		{PkgName: "example.com/a/b"},
+	}
+	totalSize := 1<<len(tests) - 1
+	pkgInfos = AttributeSymbols(nmParsers, pkgInfos, totalSize, "")
+
+	for i, tt := range tests {
+		if got := nmParsers[i].Module; got != tt.module {
+			t.Errorf("%s: %q attributed to %q, want %q", tt.name, tt.symbol, got, tt.module)
+		}
+	}
+
+	// each symbol is counted in exactly one package, the sizes are powers of 2 so the
+	// sum of a package tells which symbols are counted in it
+	infoMap := make(map[string]*PkgInfo)
+	var lines, size int
+	for _, info := range pkgInfos {
+		name := strings.TrimRight(info.PkgName, "/")
+		if _, ok := infoMap[name]; ok {
+			t.Errorf("duplicate package %q", name)
+		}
+		infoMap[name] = info
+		lines += info.Lines
+		size += info.Size
+	}
+	wantSizes := make(map[string]int)
+	var wantLines, wantSize int
+	for i, tt := range tests {
+		if tt.module == "" {
+			continue
+		}
+		wantSizes[tt.module] += 1 << i
+		wantLines++
+		wantSize += 1 << i
+	}
+	for name, want := range wantSizes {
+		info, ok := infoMap[name]
+		if !ok {
+			t.Errorf("package %q not found", name)
+			continue
+		}
+		if info.Size != want {
+			t.Errorf("package %q size = %d, want %d", name, info.Size, want)
+		}
+	}
+	if lines != wantLines || size != wantSize {
+		t.Errorf("counted %d symbols of %d bytes, want %d symbols of %d bytes", lines, size, wantLines, wantSize)
+	}
+
+	checks := map[string]func(*PkgInfo) bool{
+		"example.com/app": func(info *PkgInfo) bool { return info.IsMod },
+		"runtime":         func(info *PkgInfo) bool { return info.IsStd },
+		"net/http":        func(info *PkgInfo) bool { return info.IsStd },
+		OtherPkgName:      func(info *PkgInfo) bool { return info.IsOther && !info.IsStd },
+		"example.com/a":   func(info *PkgInfo) bool { return !info.IsMod && !info.IsStd && !info.IsOther },
+	}
+	for name, check := range checks {
+		if info, ok := infoMap[name]; !ok || !check(info) {
+			t.Errorf("package %q has unexpected flags: %+v", name, info)
+		}
+	}
+}
+
+func TestAttributeSymbolsNoModule(t *testing.T) {
+	// an archive file has no build info, all packages are grouped by their import path
+	nmParsers := []*NmParser{
+		{Symbol: "example.com/a.F", Size: 1},
+		{Symbol: "example.com/a/b.G", Size: 2},
+		{Symbol: "runtime.mallocgc", Size: 4},
+		{Symbol: "x_cgo_munmap.cold", Size: 8},
+	}
+	pkgInfos := AttributeSymbols(nmParsers, nil, 15, "")
+
+	want := map[string]bool{"example.com/a": false, "example.com/a/b": false, "runtime": true, "x_cgo_munmap": false}
+	if len(pkgInfos) != len(want) {
+		t.Fatalf("got %d packages, want %d", len(pkgInfos), len(want))
+	}
+	for _, info := range pkgInfos {
+		isStd, ok := want[info.PkgName]
+		if !ok || info.IsStd != isStd || info.Lines != 1 {
+			t.Errorf("unexpected package %+v", info)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// GoListPackage a package in the output of "go list -deps -json"
+type GoListPackage struct {
+	ImportPath string   `json:"ImportPath"`
+	Name       string   `json:"Name"`
+	Standard   bool     `json:"Standard"`
+	Imports    []string `json:"Imports"`
+}
+
+// ImportPath a chain of imports from the main package to the target package
+type ImportPath struct {
+	Packages []string `json:"packages"`
+}
+
+// WhyResult explain why a package is linked into the binary
+type WhyResult struct {
+	Target   string        `json:"target"`
+	Size     int           `json:"size"`     // size of the target package and its sub packages
+	OnlySize int           `json:"onlySize"` // size of the packages only imported through the target, including the target
+	OnlyPkgs []string      `json:"onlyPkgs"`
+	Paths    []*ImportPath `json:"paths"` // shortest import paths
+
+	pkgSizes map[string]int
+}
+
+// GoListDeps get the main package and all its dependencies by "go list -deps -json",
+// the main package is the last one, the command is run by the runner set by WithCommandRunner.
+func GoListDeps(ctx context.Context, dir string, pkg string, opts ...Option) ([]*GoListPackage, error) {
+	o := defaultOptions()
+	o.apply(opts...)
+
+	data, err := o.runner.Run(ctx, &Command{Name: "go", Args: []string{"list", "-deps", "-json", pkg}, Dir: dir})
+	if err != nil {
+		return nil, err
+	}
+
+	var pkgs []*GoListPackage
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		p := &GoListPackage{}
+		err = decoder.Decode(p)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("decode go list output failed: %v", err)
+		}
+		pkgs = append(pkgs, p)
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no package found in %s", pkg)
+	}
+
+	return pkgs, nil
+}
+
+// Why find the shortest import paths from the main package to the target, the target
+// is a package path or a module path that matches all packages under it.
+func (bp *BinaryParser) Why(pkgs []*GoListPackage, target string, maxPaths int) (*WhyResult, error) {
+	root := pkgs[len(pkgs)-1]
+	graph := make(map[string][]string, len(pkgs))
+	for _, p := range pkgs {
+		graph[p.ImportPath] = p.Imports
+	}
+	isTarget := func(path string) bool {
+		return path == target || strings.HasPrefix(path, target+"/")
+	}
+
+	// the size of each package in the binary file
+	pkgSizes := make(map[string]int)
+	for _, nm := range bp.NmParsers {
+		pkgPath := nm.PkgPath
+		if pkgPath == "main" && root.Name == "main" {
+			pkgPath = root.ImportPath
+		}
+		pkgSizes[pkgPath] += nm.Size
+	}
+
+	result := &WhyResult{Target: target, pkgSizes: pkgSizes}
+	for _, p := range pkgs {
+		if isTarget(p.ImportPath) {
+			result.Size += pkgSizes[p.ImportPath]
+		}
+	}
+
+	// breadth-first search, record all parents on the shortest paths
+	depth := map[string]int{root.ImportPath: 0}
+	parents := make(map[string][]string)
+	queue := []string{root.ImportPath}
+	var found []string
+	for len(queue) > 0 && len(found) == 0 {
+		var next []string
+		for _, path := range queue {
+			if isTarget(path) {
+				found = append(found, path)
+				continue
+			}
+			for _, imp := range graph[path] {
+				d, ok := depth[imp]
+				if !ok {
+					depth[imp] = depth[path] + 1
+					next = append(next, imp)
+				}
+				if !ok || d == depth[path]+1 {
+					parents[imp] = append(parents[imp], path)
+				}
+			}
+		}
+		queue = next
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("package %s is not imported by %s", target, root.ImportPath)
+	}
+
+	sort.Strings(found)
+	for _, path := range found {
+		collectPaths(parents, path, []string{path}, maxPaths, &result.Paths)
+	}
+
+	// the packages that can not be reached from the main package without the target
+	reachable := map[string]bool{root.ImportPath: true}
+	queue = []string{root.ImportPath}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		for _, imp := range graph[path] {
+			if !reachable[imp] && !isTarget(imp) {
+				reachable[imp] = true
+				queue = append(queue, imp)
+			}
+		}
+	}
+	for _, p := range pkgs {
+		if !reachable[p.ImportPath] {
+			result.OnlySize += pkgSizes[p.ImportPath]
+			result.OnlyPkgs = append(result.OnlyPkgs, p.ImportPath)
+		}
+	}
+
+	return result, nil
+}
+
+// walk back from the target to the main package through the parents
+func collectPaths(parents map[string][]string, path string, chain []string, maxPaths int, paths *[]*ImportPath) {
+	if len(*paths) >= maxPaths {
+		return
+	}
+	ps := parents[path]
+	if len(ps) == 0 {
+		packages := make([]string, len(chain))
+		for i := range chain {
+			packages[i] = chain[len(chain)-1-i]
+		}
+		*paths = append(*paths, &ImportPath{Packages: packages})
+		return
+	}
+	sorted := append([]string{}, ps...)
+	sort.Strings(sorted)
+	for _, p := range sorted {
+		collectPaths(parents, p, append(chain, p), maxPaths, paths)
+	}
+}
+
+// PrintJSON print the result in json format to the writer.
+func (r *WhyResult) PrintJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Print print the import paths and the size they bring in to the writer.
+func (r *WhyResult) Print(w io.Writer, binaryFile string, totalSize int) {
+	percentage := func(size int) string {
+		if totalSize <= 0 {
+			return "0.00%"
+		}
+		return fmt.Sprintf("%.2f%%", float32(size)/float32(totalSize)*100)
+	}
+
+	fmt.Fprintf(w, "\nwhy \"%s\" is linked into binary file \"%s\":\n", r.Target, binaryFile)
+	fmt.Fprintf(w, "package size: %s bytes (%s),  only imported through it: %s bytes (%s), %s packages\n",
+		color.HiGreenString(strconv.Itoa(r.Size)), percentage(r.Size),
+		color.HiRedString(strconv.Itoa(r.OnlySize)), percentage(r.OnlySize),
+		color.HiCyanString(strconv.Itoa(len(r.OnlyPkgs))))
+
+	depth := 0
+	if len(r.Paths) > 0 {
+		depth = len(r.Paths[0].Packages) - 1
+	}
+	fmt.Fprintf(w, "\nshortest import paths (depth %s), show %s paths:\n",
+		color.HiCyanString(strconv.Itoa(depth)),
+		color.HiMagentaString(strconv.Itoa(len(r.Paths))))
+	for i, p := range r.Paths {
+		for j, pkg := range p.Packages {
+			prefix := fmt.Sprintf("  %d. ", i+1)
+			if j > 0 {
+				prefix = strings.Repeat(" ", len(prefix)) + "-> "
+			}
+			fmt.Fprintf(w, "%s%s %s\n", prefix, pkg, color.HiBlackString("(%d bytes)", r.pkgSizes[pkg]))
+		}
+	}
+}
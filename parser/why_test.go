@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// main => a => target => only => only/deep, main => b => target and other, target => target/sub
+const whyGoListOutput = `{"ImportPath": "errors", "Name": "errors", "Standard": true}
+{"ImportPath": "github.com/only/deep", "Name": "deep"}
+{"ImportPath": "github.com/only", "Name": "only", "Imports": ["github.com/only/deep"]}
+{"ImportPath": "github.com/t/target/sub", "Name": "sub", "Imports": ["errors"]}
+{"ImportPath": "github.com/t/target", "Name": "target", "Imports": ["errors", "github.com/only", "github.com/t/target/sub"]}
+{"ImportPath": "github.com/other", "Name": "other", "Imports": ["errors"]}
+{"ImportPath": "example.com/app/a", "Name": "a", "Imports": ["github.com/t/target"]}
+{"ImportPath": "example.com/app/b", "Name": "b", "Imports": ["github.com/other", "github.com/t/target"]}
+{"ImportPath": "example.com/app", "Name": "main", "Imports": ["errors", "example.com/app/a", "example.com/app/b"]}
+`
+
+func TestWhy(t *testing.T) {
+	runner := &fakeRunner{outputs: map[string]string{"go list -deps -json .": whyGoListOutput}}
+	pkgs, err := GoListDeps(context.Background(), ".", ".", WithCommandRunner(runner))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bp := &BinaryParser{NmParsers: []*NmParser{
+		{Symbol: "main.main", PkgPath: "main", Size: 1},
+		{Symbol: "errors.New", PkgPath: "errors", Size: 2},
+		{Symbol: "example.com/app/a.A", PkgPath: "example.com/app/a", Size: 4},
+		{Symbol: "example.com/app/b.B", PkgPath: "example.com/app/b", Size: 8},
+		{Symbol: "github.com/t/target.T", PkgPath: "github.com/t/target", Size: 16},
+		{Symbol: "github.com/t/target/sub.S", PkgPath: "github.com/t/target/sub", Size: 32},
+		{Symbol: "github.com/only.O", PkgPath: "github.com/only", Size: 64},
+		{Symbol: "github.com/only/deep.D", PkgPath: "github.com/only/deep", Size: 128},
+		{Symbol: "github.com/other.X", PkgPath: "github.com/other", Size: 256},
+	}}
+
+	tests := []struct {
+		name     string
+		target   string
+		maxPaths int
+		want     *WhyResult
+		wantErr  bool
+	}{
+		{
+			name:     "diamond",
+			target:   "github.com/t/target",
+			maxPaths: 5,
+			want: &WhyResult{
+				Size:     16 + 32,
+				OnlySize: 128 + 64 + 32 + 16,
+				OnlyPkgs: []string{"github.com/only/deep", "github.com/only", "github.com/t/target/sub", "github.com/t/target"},
+				Paths: []*ImportPath{
+					{Packages: []string{"example.com/app", "example.com/app/a", "github.com/t/target"}},
+					{Packages: []string{"example.com/app", "example.com/app/b", "github.com/t/target"}},
+				},
+			},
+		},
+		{
+			name:     "only reachable through another package",
+			target:   "github.com/only/deep",
+			maxPaths: 5,
+			want: &WhyResult{
+				Size:     128,
+				OnlySize: 128,
+				OnlyPkgs: []string{"github.com/only/deep"},
+				Paths: []*ImportPath{
+					{Packages: []string{"example.com/app", "example.com/app/a", "github.com/t/target", "github.com/only", "github.com/only/deep"}},
+					{Packages: []string{"example.com/app", "example.com/app/b", "github.com/t/target", "github.com/only", "github.com/only/deep"}},
+				},
+			},
+		},
+		{
+			name:     "max paths",
+			target:   "github.com/t/target",
+			maxPaths: 1,
+			want: &WhyResult{
+				Size:     16 + 32,
+				OnlySize: 128 + 64 + 32 + 16,
+				OnlyPkgs: []string{"github.com/only/deep", "github.com/only", "github.com/t/target/sub", "github.com/t/target"},
+				Paths: []*ImportPath{
+					{Packages: []string{"example.com/app", "example.com/app/a", "github.com/t/target"}},
+				},
+			},
+		},
+		{
+			name:     "imported directly by the main package",
+			target:   "errors",
+			maxPaths: 5,
+			want: &WhyResult{
+				Size:     2,
+				OnlySize: 2,
+				OnlyPkgs: []string{"errors"},
+				Paths:    []*ImportPath{{Packages: []string{"example.com/app", "errors"}}},
+			},
+		},
+		{
+			name:     "not imported",
+			target:   "github.com/missing",
+			maxPaths: 5,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bp.Why(pkgs, tt.target, tt.maxPaths)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Why(%q) expected an error", tt.target)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Size != tt.want.Size || got.OnlySize != tt.want.OnlySize {
+				t.Errorf("Why(%q) size = %d, only size = %d, want %d, %d", tt.target, got.Size, got.OnlySize, tt.want.Size, tt.want.OnlySize)
+			}
+			if !reflect.DeepEqual(got.OnlyPkgs, tt.want.OnlyPkgs) {
+				t.Errorf("Why(%q) only packages = %v, want %v", tt.target, got.OnlyPkgs, tt.want.OnlyPkgs)
+			}
+			if !reflect.DeepEqual(got.Paths, tt.want.Paths) {
+				for _, p := range got.Paths {
+					t.Logf("%v", p.Packages)
+				}
+				t.Errorf("Why(%q) got %d paths, want %d", tt.target, len(got.Paths), len(tt.want.Paths))
+			}
+		})
+	}
+}
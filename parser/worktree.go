@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BuildRevision check out the revision of the git repository that dir belongs to in a temporary
+// worktree under outDir, build the main package and return the path of the binary file, the
+// package and source directory in opts are relative to dir. The worktree is removed after the build.
+func BuildRevision(ctx context.Context, dir string, revision string, opts *BuildOptions, outDir string) (string, error) {
+	if dir == "" {
+		dir = "."
+	}
+	runner := runnerOrDefault(opts.Runner)
+	git := func(ctx context.Context, args ...string) ([]byte, error) {
+		return runner.Run(ctx, &Command{Name: "git", Args: args, Dir: dir})
+	}
+
+	out, err := git(ctx, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", fmt.Errorf("%s is not in a git repository: %v", dir, err)
+	}
+	prefix := strings.TrimSpace(string(out))
+
+	err = os.MkdirAll(outDir, 0755)
+	if err != nil {
+		return "", err
+	}
+	worktree, err := filepath.Abs(filepath.Join(outDir, "src"))
+	if err != nil {
+		return "", err
+	}
+	_, err = git(ctx, "worktree", "add", "--detach", worktree, revision)
+	if err != nil {
+		return "", fmt.Errorf("check out revision %s failed: %v", revision, err)
+	}
+	defer func() {
+		// remove the worktree even if the context is canceled
+		_, _ = git(context.Background(), "worktree", "remove", "--force", worktree)
+	}()
+
+	revOpts := *opts
+	revOpts.Dir = filepath.Join(worktree, prefix)
+	return BuildBinary(ctx, &revOpts, outDir)
+}